@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"blinkcli/internal/auth"
+	"blinkcli/internal/config"
+)
+
+func authCmd() *command {
+	return &command{
+		Name:  "auth",
+		Short: "Log in, refresh or inspect the Blinkit session",
+		Subcommands: []*command{
+			authLoginCmd(),
+			authRefreshCmd(),
+			authImportCmd(),
+			authStatusCmd(),
+			authLogoutCmd(),
+		},
+	}
+}
+
+func authLoginCmd() *command {
+	var (
+		profileDir   string
+		timeout      time.Duration
+		remoteURL    string
+		chromePath   string
+		locationName string
+	)
+	return &command{
+		Name:  "login",
+		Short: "Log in through a browser and save the session",
+		Long: `Log in through a browser and save the session.
+
+A Chrome/Chromium window opens; log in and select an address. The session
+and the selected address are captured automatically.`,
+		Examples: []string{
+			"blinkcli auth login",
+			"blinkcli auth login --profile-dir ~/.local/share/blinkcli/chrome",
+			"blinkcli auth login --remote-debugging-url http://127.0.0.1:9222",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&profileDir, "profile-dir", "", "persistent Chrome profile directory (reused by 'auth refresh')")
+			fs.DurationVar(&timeout, "timeout", 8*time.Minute, "how long to wait for login")
+			fs.StringVar(&remoteURL, "remote-debugging-url", "", "attach to a running Chrome (ws://... or http://host:port)")
+			fs.StringVar(&chromePath, "chrome-path", "", "Chrome/Chromium executable to launch")
+			fs.StringVar(&locationName, "location-name", "default", "name to save the selected address under")
+		},
+		Run: func(a *app, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := config.CheckSecretBackend(cfg); err != nil {
+				return err
+			}
+			session, err := auth.Login(context.Background(), auth.LoginOptions{
+				ProfileDir: profileDir,
+				Timeout:    timeout,
+				RemoteURL:  remoteURL,
+				ChromePath: chromePath,
+			})
+			if err != nil {
+				return err
+			}
+			cfg.Session = session
+			if profileDir != "" && remoteURL == "" {
+				cfg.BrowserProfileDir = profileDir
+			}
+			saveSessionLocation(cfg, locationName)
+			if err := config.Save(cfg); err != nil {
+				return err
+			}
+			fmt.Fprintln(a.stdout, "Login captured and saved.")
+			return nil
+		},
+	}
+}
+
+func authRefreshCmd() *command {
+	var (
+		profileDir string
+		timeout    time.Duration
+		remoteURL  string
+		chromePath string
+	)
+	return &command{
+		Name:  "refresh",
+		Short: "Re-read the session from a browser profile without user interaction",
+		Long: `Re-read the session from a browser profile without user interaction.
+
+Starts Chrome headlessly with the persistent profile from the last
+'auth login --profile-dir' (or --profile-dir) and saves the session found
+there. With --remote-debugging-url the session is read from a running Chrome.`,
+		Examples: []string{
+			"blinkcli auth refresh",
+			"blinkcli auth status --check >/dev/null || blinkcli auth refresh",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&profileDir, "profile-dir", "", "persistent Chrome profile directory (defaults to the one used at login)")
+			fs.DurationVar(&timeout, "timeout", 45*time.Second, "how long to wait for the session")
+			fs.StringVar(&remoteURL, "remote-debugging-url", "", "read the session from a running Chrome instead")
+			fs.StringVar(&chromePath, "chrome-path", "", "Chrome/Chromium executable to launch")
+		},
+		Run: func(a *app, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := config.CheckSecretBackend(cfg); err != nil {
+				return err
+			}
+			dir := profileDir
+			if dir == "" {
+				dir = cfg.BrowserProfileDir
+			}
+			if dir == "" && remoteURL == "" {
+				return withExit(exitAuth, fmt.Errorf("no browser profile; run 'blinkcli auth login --profile-dir <dir>' first"))
+			}
+			a.debugf("refreshing from profile %q", dir)
+			session, err := auth.Refresh(context.Background(), auth.LoginOptions{
+				ProfileDir: dir,
+				Timeout:    timeout,
+				RemoteURL:  remoteURL,
+				ChromePath: chromePath,
+			})
+			if err != nil {
+				return withExit(exitAuth, err)
+			}
+			cfg.Session = session
+			if remoteURL == "" {
+				cfg.BrowserProfileDir = dir
+			}
+			if err := config.Save(cfg); err != nil {
+				return err
+			}
+			fmt.Fprintln(a.stdout, "Session refreshed and saved.")
+			return nil
+		},
+	}
+}
+
+func authImportCmd() *command {
+	var (
+		harPath     string
+		cookiesPath string
+		jsonPath    string
+		noFetch     bool
+	)
+	return &command{
+		Name:  "import",
+		Short: "Import a session from a HAR capture or cookie export",
+		Long: `Import a session from a HAR capture or cookie export.
+
+access_token and device_id are required. A missing auth_key is fetched from
+Blinkit unless --no-fetch is given; other missing fields are listed.`,
+		Examples: []string{
+			"blinkcli auth import --har blinkit.har",
+			"blinkcli auth import --cookies cookies.txt",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&harPath, "har", "", "HAR capture of a logged-in blinkit.com tab")
+			fs.StringVar(&cookiesPath, "cookies", "", "Netscape cookies.txt export")
+			fs.StringVar(&jsonPath, "json", "", "JSON cookie export ([{name, value, domain}])")
+			fs.BoolVar(&noFetch, "no-fetch", false, "don't contact Blinkit to fill a missing auth_key")
+		},
+		Run: func(a *app, args []string) error {
+			var (
+				path   string
+				parser func(io.Reader) (*config.Session, error)
+				chosen int
+			)
+			if harPath != "" {
+				path, parser = harPath, auth.ImportHAR
+				chosen++
+			}
+			if cookiesPath != "" {
+				path, parser = cookiesPath, auth.ImportNetscapeCookies
+				chosen++
+			}
+			if jsonPath != "" {
+				path, parser = jsonPath, auth.ImportCookieJSON
+				chosen++
+			}
+			if chosen != 1 {
+				return usageErrorf("specify exactly one of --har, --cookies or --json")
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if err := config.CheckSecretBackend(cfg); err != nil {
+				return err
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			session, err := parser(f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+			missing, err := auth.FinishImport(session, !noFetch)
+			if err != nil {
+				return err
+			}
+
+			cfg.Session = session
+			saveSessionLocation(cfg, "default")
+			if err := config.Save(cfg); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.stdout, "Session imported from %s and saved.\n", path)
+			if len(missing) > 0 {
+				fmt.Fprintf(a.stdout, "Missing fields: %s (requests may fail until you log in with a browser).\n", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func authStatusCmd() *command {
+	var (
+		check    bool
+		location string
+	)
+	return &command{
+		Name:  "status",
+		Short: "Show the stored session, optionally validating it",
+		Long: `Show the stored session, optionally validating it.
+
+With --check a lightweight authenticated request is made. The exit code is 0
+when the session is valid, 3 when it is missing or rejected, and 4 when the
+check was inconclusive (e.g. network errors).`,
+		Examples: []string{
+			"blinkcli auth status --check",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&check, "check", false, "validate the session with a live request")
+			fs.StringVar(&location, "location", "", "saved location to check with")
+		},
+		FlagValues: map[string]func(a *app) []string{"location": completeLocations},
+		Run: func(a *app, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			msg, ok := auth.Status(cfg)
+			fmt.Fprintln(a.stdout, msg)
+			if !check {
+				return nil
+			}
+			if !ok {
+				return silentExit(exitAuth)
+			}
+			if _, err := cfg.ApplyLocation(location); err != nil {
+				return err
+			}
+			report := auth.Check(context.Background(), cfg.Session, time.Now())
+			fmt.Fprintln(a.stdout, auth.FormatCheck(report))
+			switch report.State {
+			case auth.StateExpired:
+				return silentExit(exitAuth)
+			case auth.StateUnknown:
+				return silentExit(exitUnknown)
+			}
+			return nil
+		},
+	}
+}
+
+func authLogoutCmd() *command {
+	return &command{
+		Name:  "logout",
+		Short: "Clear the locally stored session",
+		Run: func(a *app, args []string) error {
+			if err := config.Clear(); err != nil {
+				return err
+			}
+			fmt.Fprintln(a.stdout, "Logged out (local session cleared).")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/budget"
+	"blinkcli/internal/category"
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+)
+
+// errBudgetExceeded is returned by sync with --fail-on-budget.
+var errBudgetExceeded = errors.New("budget exceeded")
+
+func budgetCmd() *command {
+	return &command{
+		Name:  "budget",
+		Short: "Show spend against the profile's budget",
+		Long: `Show spend against the profile's budget.
+
+Lists each limit with the amount spent in the current period, the share used,
+what is left and the spend projected for the end of the period at the
+current pace. Weeks start on Monday; category limits are monthly. Cancelled
+orders are not counted. Items are categorized by the rules of 'blinkcli
+categories'; orders only carry a total, so category spend splits each
+order's amount evenly across its items.
+
+'blinkcli sync' warns when a limit is exceeded, and exits with status 5 when
+given --fail-on-budget.`,
+		Examples: []string{
+			"blinkcli budget set --monthly 15000 --weekly 4000",
+			"blinkcli budget set --category dairy=2500 --category snacks=1000",
+			"blinkcli budget",
+		},
+		Subcommands: []*command{budgetSetCmd(), budgetClearCmd()},
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			cfg, err := config.LoadFile()
+			if err != nil {
+				return err
+			}
+			if cfg.Budget.IsZero() {
+				fmt.Fprintln(a.stdout, "No budget set. Run 'blinkcli budget set --monthly <amount>'.")
+				return nil
+			}
+			orders, err := loadOrders(false)
+			if err != nil {
+				return err
+			}
+			statuses, err := evaluateBudget(cfg, orders)
+			if err != nil {
+				return err
+			}
+			return format.Write(a.stdout, output, budget.Output(statuses))
+		},
+	}
+}
+
+func budgetSetCmd() *command {
+	var (
+		monthly, weekly int
+		categories      []string
+	)
+	return &command{
+		Name:  "set",
+		Short: "Set monthly, weekly or per-category limits",
+		Long: `Set monthly, weekly or per-category limits in rupees.
+
+Only the limits given change. --category takes name=amount and may be
+repeated or comma-separated; an amount of 0 removes a limit, as does 0 for
+--monthly or --weekly.`,
+		Examples: []string{
+			"blinkcli budget set --monthly 15000",
+			"blinkcli budget set --category dairy=2500,snacks=1000",
+			"blinkcli budget set --weekly 0",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&monthly, "monthly", 0, "monthly limit in rupees")
+			fs.IntVar(&weekly, "weekly", 0, "weekly limit in rupees")
+			fs.Func("category", "monthly category limit as name=amount (repeatable)", func(value string) error {
+				for _, spec := range strings.Split(value, ",") {
+					if spec = strings.TrimSpace(spec); spec != "" {
+						categories = append(categories, spec)
+					}
+				}
+				return nil
+			})
+		},
+		FlagValues: map[string]func(a *app) []string{"category": completeCategories},
+		Run: func(a *app, args []string) error {
+			if !a.flagSet("monthly") && !a.flagSet("weekly") && len(categories) == 0 {
+				return usageErrorf("give --monthly, --weekly or --category")
+			}
+			if monthly < 0 || weekly < 0 {
+				return usageErrorf("limits cannot be negative")
+			}
+			return updateConfig(func(cfg *config.Config) error {
+				b := cfg.Budget
+				if b == nil {
+					b = &config.Budget{}
+				}
+				if a.flagSet("monthly") {
+					b.Monthly = monthly
+				}
+				if a.flagSet("weekly") {
+					b.Weekly = weekly
+				}
+				categorizer, err := category.New(cfg.CategoryRules)
+				if err != nil {
+					return err
+				}
+				for _, spec := range categories {
+					if err := b.SetCategoryLimit(spec); err != nil {
+						return withExit(exitUsage, err)
+					}
+					if name, _, _ := strings.Cut(spec, "="); !categorizer.Has(strings.TrimSpace(name)) {
+						fmt.Fprintf(a.stderr, "Warning: no rule assigns items to %q; see 'blinkcli categories list'.\n", strings.TrimSpace(name))
+					}
+				}
+				cfg.Budget = b
+				if b.IsZero() {
+					cfg.Budget = nil
+				}
+				fmt.Fprintln(a.stdout, "Budget updated.")
+				return nil
+			})
+		},
+	}
+}
+
+func budgetClearCmd() *command {
+	return &command{
+		Name:  "clear",
+		Short: "Remove every limit",
+		Run: func(a *app, args []string) error {
+			return updateConfig(func(cfg *config.Config) error {
+				cfg.Budget = nil
+				fmt.Fprintln(a.stdout, "Budget cleared.")
+				return nil
+			})
+		},
+	}
+}
+
+// evaluateBudget measures the budget of cfg against orders in the configured
+// time zone, categorizing items with cfg's rules.
+func evaluateBudget(cfg *config.Config, orders []blink.Order) ([]budget.Status, error) {
+	tz, err := config.TimeZone()
+	if err != nil {
+		return nil, err
+	}
+	categorizer, err := category.New(cfg.CategoryRules)
+	if err != nil {
+		return nil, err
+	}
+	return budget.Evaluate(cfg.Budget, orders, time.Now().In(tz), categorizer.Category), nil
+}
+
+// warnBudget prints a warning to stderr for every exceeded limit and reports
+// whether there was one.
+func warnBudget(a *app, profile string, cfg *config.Config, orders []blink.Order) (bool, error) {
+	if cfg.Budget.IsZero() {
+		return false, nil
+	}
+	statuses, err := evaluateBudget(cfg, orders)
+	if err != nil {
+		return false, err
+	}
+	over := budget.Exceeded(statuses)
+	for _, s := range over {
+		fmt.Fprintf(a.stderr, "Warning: %s: %s\n", profile, budget.Warning(s))
+	}
+	return len(over) > 0, nil
+}
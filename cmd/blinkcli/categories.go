@@ -0,0 +1,317 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"blinkcli/internal/category"
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+	"blinkcli/internal/stats"
+)
+
+func categoriesCmd() *command {
+	return &command{
+		Name:  "categories",
+		Short: "Manage the rules that put items into categories",
+		Long: `Manage the rules that put items into categories.
+
+Items are categorized by keyword and regular expression rules. Keywords match
+whole words, case-insensitively, with an optional plural ("egg" matches "Farm
+Eggs" but not "Eggless"); patterns are Go regular expressions. Built-in rules
+cover common groceries; rules added here are checked first. When several
+rules match, the longest match wins, so "Peanut Butter" is a spread rather
+than dairy.
+
+Categories feed the "By category" section of 'blinkcli stats' and category
+limits in 'blinkcli budget'.`,
+		Examples: []string{
+			"blinkcli categories uncategorized",
+			"blinkcli categories test 'Pintola Peanut Butter'",
+			"blinkcli categories test --pattern 'protein (bar|shake)'",
+			"blinkcli categories add fitness --keyword whey,protein --pattern 'protein (bar|shake)'",
+		},
+		Subcommands: []*command{
+			categoriesListCmd(),
+			categoriesUncategorizedCmd(),
+			categoriesTestCmd(),
+			categoriesAddCmd(),
+			{
+				Name:      "remove",
+				Args:      "<category>",
+				Short:     "Delete the config rule for a category",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeConfigCategories,
+				Run: func(a *app, args []string) error {
+					return updateConfig(func(cfg *config.Config) error {
+						if !cfg.RemoveCategoryRule(args[0]) {
+							return fmt.Errorf("no config rule for category %q (built-in rules cannot be removed)", args[0])
+						}
+						fmt.Fprintf(a.stdout, "Rule for %s removed.\n", args[0])
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
+
+func categoriesListCmd() *command {
+	return &command{
+		Name:  "list",
+		Short: "List categories with their rules",
+		Run: func(a *app, args []string) error {
+			cfg, err := config.LoadFile()
+			if err != nil {
+				return err
+			}
+			width := a.termWidth()
+			for _, group := range []struct {
+				title string
+				rules []config.CategoryRule
+			}{
+				{"Config rules (checked first):", cfg.CategoryRules},
+				{"Built-in rules:", category.DefaultRules()},
+			} {
+				if len(group.rules) == 0 {
+					continue
+				}
+				fmt.Fprintln(a.stdout, group.title)
+				for _, rule := range group.rules {
+					terms := append([]string{}, rule.Keywords...)
+					for _, p := range rule.Patterns {
+						terms = append(terms, "/"+p+"/")
+					}
+					line := fmt.Sprintf("  %s: %s", rule.Category, strings.Join(terms, ", "))
+					if width > 0 {
+						line = format.Truncate(line, width)
+					}
+					fmt.Fprintln(a.stdout, line)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func categoriesUncategorizedCmd() *command {
+	var (
+		allProfiles bool
+		top         int
+	)
+	return &command{
+		Name:  "uncategorized",
+		Short: "List stored items no rule matches",
+		Long: `List stored items no rule matches, most often ordered first.
+
+Columns are those of 'blinkcli stats items'. Add rules for the items that
+matter with 'blinkcli categories add'.`,
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "use orders from all profiles")
+			fs.IntVar(&top, "top", 0, "number of items to list (0 = all)")
+		},
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			categorizer, err := loadCategorizer()
+			if err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			orders, err := loadOrders(allProfiles)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			var items []stats.ItemStat
+			for _, item := range stats.ItemStats(orders, time.Now().In(tz), stats.ByOrders, 0) {
+				if categorizer.Category(item.Name) == "" {
+					items = append(items, item)
+				}
+			}
+			if top > 0 && len(items) > top {
+				items = items[:top]
+			}
+			if len(items) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, "Every stored item has a category.")
+				return nil
+			}
+			return format.Write(a.stdout, output, stats.ItemsOutput(items, a.termWidth()))
+		},
+	}
+}
+
+func categoriesTestCmd() *command {
+	var rule config.CategoryRule
+	return &command{
+		Name:  "test",
+		Args:  "[item...]",
+		Short: "Show how items are categorized, or what a new rule would match",
+		Long: `Show how items are categorized, or what a new rule would match.
+
+With item names, prints the category of each and the rule that matched.
+With --keyword or --pattern instead, lists the stored items the rule would
+match, with their current category, before it is added.`,
+		Examples: []string{
+			"blinkcli categories test 'Amul Taaza Milk' 'Cadbury Dairy Milk'",
+			"blinkcli categories test --keyword whey --pattern 'protein (bar|shake)'",
+		},
+		MaxArgs:   -1,
+		ValidArgs: completeItems,
+		Flags: func(fs *flag.FlagSet) {
+			ruleFlags(fs, &rule)
+		},
+		Run: func(a *app, args []string) error {
+			if len(rule.Keywords) == 0 && len(rule.Patterns) == 0 {
+				if len(args) == 0 {
+					return usageErrorf("give item names, or --keyword or --pattern to try a rule")
+				}
+				categorizer, err := loadCategorizer()
+				if err != nil {
+					return err
+				}
+				for _, item := range args {
+					m, ok := categorizer.Match(item)
+					if !ok {
+						fmt.Fprintf(a.stdout, "%s: %s\n", item, category.Uncategorized)
+						continue
+					}
+					fmt.Fprintf(a.stdout, "%s: %s (%q, %s rule)\n", item, m.Category, m.Rule, m.Source)
+				}
+				return nil
+			}
+			if len(args) > 0 {
+				return usageErrorf("give item names or a rule, not both")
+			}
+
+			rule.Category = "(new rule)"
+			candidate, err := category.New([]config.CategoryRule{rule})
+			if err != nil {
+				return withExit(exitUsage, err)
+			}
+			categorizer, err := loadCategorizer()
+			if err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			orders, err := loadOrders(false)
+			if err != nil {
+				return err
+			}
+			matched := 0
+			for _, item := range stats.ItemStats(orders, time.Now().In(tz), stats.ByOrders, 0) {
+				if m, _ := candidate.Match(item.Name); m.Source != category.SourceConfig {
+					continue
+				}
+				current := categorizer.Category(item.Name)
+				if current == "" {
+					current = category.Uncategorized
+				}
+				fmt.Fprintf(a.stdout, "%s (%d orders, now %s)\n", item.Name, item.Orders, current)
+				matched++
+			}
+			if matched == 0 {
+				fmt.Fprintln(a.stdout, "The rule matches no stored items.")
+			}
+			return nil
+		},
+	}
+}
+
+func categoriesAddCmd() *command {
+	var rule config.CategoryRule
+	return &command{
+		Name:  "add",
+		Args:  "<category>",
+		Short: "Add keywords or patterns to a category",
+		Long: `Add keywords or patterns to a category.
+
+The rule is saved in the profile's config and checked before the built-in
+rules; adding to an existing category merges into its rule. Try a rule first
+with 'blinkcli categories test --keyword ...'.`,
+		Examples: []string{
+			"blinkcli categories add fitness --keyword whey,protein",
+			"blinkcli categories add snacks --pattern 'trail ?mix'",
+		},
+		MinArgs:   1,
+		MaxArgs:   1,
+		ValidArgs: completeCategories,
+		Flags: func(fs *flag.FlagSet) {
+			ruleFlags(fs, &rule)
+		},
+		Run: func(a *app, args []string) error {
+			if len(rule.Keywords) == 0 && len(rule.Patterns) == 0 {
+				return usageErrorf("give --keyword or --pattern")
+			}
+			rule.Category = args[0]
+			if err := category.Validate(rule); err != nil {
+				return withExit(exitUsage, err)
+			}
+			return updateConfig(func(cfg *config.Config) error {
+				cfg.AddCategoryRule(rule)
+				fmt.Fprintf(a.stdout, "Rule for %s saved.\n", strings.ToLower(args[0]))
+				return nil
+			})
+		},
+	}
+}
+
+// ruleFlags registers --keyword (repeatable or comma-separated) and
+// --pattern (repeatable) into rule.
+func ruleFlags(fs *flag.FlagSet, rule *config.CategoryRule) {
+	fs.Func("keyword", "whole-word keyword (repeatable or comma-separated)", func(value string) error {
+		for _, kw := range strings.Split(value, ",") {
+			if kw = strings.TrimSpace(kw); kw != "" {
+				rule.Keywords = append(rule.Keywords, kw)
+			}
+		}
+		return nil
+	})
+	fs.Func("pattern", "case-insensitive regular expression (repeatable)", func(value string) error {
+		rule.Patterns = append(rule.Patterns, value)
+		return nil
+	})
+}
+
+// loadCategorizer compiles the active profile's category rules.
+func loadCategorizer() (*category.Categorizer, error) {
+	cfg, err := config.LoadFile()
+	if err != nil {
+		return nil, err
+	}
+	return category.New(cfg.CategoryRules)
+}
+
+func completeCategories(a *app) []string {
+	categorizer, err := loadCategorizer()
+	if err != nil {
+		return nil
+	}
+	return categorizer.Categories()
+}
+
+func completeConfigCategories(a *app) []string {
+	cfg, err := config.LoadFile()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.CategoryRules))
+	for _, rule := range cfg.CategoryRules {
+		names = append(names, rule.Category)
+	}
+	return names
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+)
+
+// Exit codes shared by all commands.
+const (
+	exitOK = 0
+	// exitError is any failure without a more specific code.
+	exitError = 1
+	// exitUsage means unknown commands, bad flags or wrong arguments.
+	exitUsage = 2
+	// exitAuth means not logged in or the session was rejected.
+	exitAuth = 3
+	// exitUnknown means a check could not reach a verdict.
+	exitUnknown = 4
+	// exitBudget means a budget limit was exceeded (sync --fail-on-budget).
+	exitBudget = 5
+)
+
+// command is a node in the CLI tree. Groups have Subcommands; leaves have
+// Run. A command may have both, in which case a matching first argument
+// selects the subcommand.
+type command struct {
+	Name  string
+	Args  string
+	Short string
+	Long  string
+	// Examples are full command lines shown in help.
+	Examples []string
+	// MinArgs and MaxArgs bound positional arguments; MaxArgs -1 is unlimited.
+	MinArgs     int
+	MaxArgs     int
+	Flags       func(fs *flag.FlagSet)
+	Run         func(a *app, args []string) error
+	Subcommands []*command
+	Hidden      bool
+	// RawArgs passes all arguments to Run without flag parsing.
+	RawArgs bool
+	// ValidArgs and FlagValues feed shell completion for the first
+	// positional argument and for flag values.
+	ValidArgs  func(a *app) []string
+	FlagValues map[string]func(a *app) []string
+
+	parent *command
+}
+
+// app carries global flag values and output streams into commands.
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	profile   string
+	configDir string
+	output    string
+	verbose   bool
+	noColor   bool
+
+	// setFlags records the flags given on the command line for the command
+	// being run, so settings-backed defaults can tell them apart.
+	setFlags map[string]bool
+}
+
+// exitStatus carries a specific exit code out of a command.
+type exitStatus struct {
+	code int
+	err  error
+}
+
+func (e *exitStatus) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitStatus) Unwrap() error { return e.err }
+
+func withExit(code int, err error) error {
+	return &exitStatus{code: code, err: err}
+}
+
+func usageErrorf(format string, args ...any) error {
+	return withExit(exitUsage, fmt.Errorf(format, args...))
+}
+
+// silentExit ends the command with a code and no error message.
+func silentExit(code int) error {
+	return &exitStatus{code: code}
+}
+
+func (c *command) path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.path() + " " + c.Name
+}
+
+func (c *command) find(name string) *command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+func (c *command) link() *command {
+	for _, sub := range c.Subcommands {
+		sub.parent = c
+		sub.link()
+	}
+	return c
+}
+
+func (a *app) globalFlags(fs *flag.FlagSet) {
+	fs.StringVar(&a.profile, "profile", a.profile, "profile to use (env "+config.ProfileEnv+")")
+	fs.StringVar(&a.configDir, "config", a.configDir, "config directory (env "+config.ConfigDirEnv+")")
+	fs.StringVar(&a.output, "output", a.output, "output format (setting 'output')")
+	fs.BoolVar(&a.verbose, "verbose", a.verbose, "print progress details to stderr")
+	fs.BoolVar(&a.noColor, "no-color", a.noColor, "disable colored output (also NO_COLOR)")
+}
+
+func isGlobalFlag(name string) bool {
+	switch name {
+	case "profile", "config", "output", "verbose", "no-color":
+		return true
+	}
+	return false
+}
+
+func (a *app) newFlagSet(c *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.path(), flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if c.Flags != nil {
+		c.Flags(fs)
+	}
+	a.globalFlags(fs)
+	return fs
+}
+
+// execute runs the command selected by args and returns the process exit code.
+func (a *app) execute(root *command, args []string) int {
+	err := a.dispatch(root.link(), args)
+	if err == nil {
+		return exitOK
+	}
+	code := exitError
+	var ee *exitStatus
+	if errors.As(err, &ee) {
+		code = ee.code
+		if ee.err == nil {
+			return code
+		}
+	}
+	fmt.Fprintf(a.stderr, "Error: %v\n", err)
+	return code
+}
+
+func (a *app) dispatch(cmd *command, args []string) error {
+	for {
+		if len(args) > 0 {
+			if sub := cmd.find(args[0]); sub != nil {
+				cmd, args = sub, args[1:]
+				continue
+			}
+		}
+
+		if cmd.RawArgs {
+			return cmd.Run(a, args)
+		}
+		fs := a.newFlagSet(cmd)
+		rest, err := parseInterspersed(fs, args)
+		if err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				a.printHelp(cmd)
+				return nil
+			}
+			return usageErrorf("%v (see '%s --help')", err, cmd.path())
+		}
+		if len(rest) > 0 && len(cmd.Subcommands) > 0 {
+			if sub := cmd.find(rest[0]); sub != nil {
+				cmd, args = sub, rest[1:]
+				continue
+			}
+		}
+
+		if err := a.applyGlobals(); err != nil {
+			return withExit(exitUsage, err)
+		}
+		if cmd.Run == nil {
+			if len(rest) > 0 {
+				return usageErrorf("unknown command %q for '%s' (see '%s --help')", rest[0], cmd.path(), cmd.path())
+			}
+			a.printHelp(cmd)
+			return silentExit(exitUsage)
+		}
+		if len(rest) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(rest) > cmd.MaxArgs) {
+			return usageErrorf("usage: %s (see '%s --help')", cmd.synopsis(), cmd.path())
+		}
+		a.setFlags = map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { a.setFlags[f.Name] = true })
+		return cmd.Run(a, rest)
+	}
+}
+
+// parseInterspersed parses flags anywhere among the arguments, so they may
+// follow positional ones. Everything after "--" is positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+func (a *app) applyGlobals() error {
+	if a.configDir != "" {
+		if err := os.Setenv(config.ConfigDirEnv, a.configDir); err != nil {
+			return err
+		}
+	}
+	profile := a.profile
+	if profile == "" {
+		profile = os.Getenv(config.ProfileEnv)
+	}
+	if profile != "" {
+		if err := config.SetProfile(profile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flagSet reports whether a flag was given on the command line.
+func (a *app) flagSet(name string) bool {
+	return a.setFlags[name]
+}
+
+// settingInt returns the flag value when given, else the resolved setting.
+func (a *app) settingInt(flagName, key string, value int) (int, error) {
+	if a.flagSet(flagName) {
+		return value, nil
+	}
+	return config.SettingInt(key)
+}
+
+// outputFormat resolves --output against the 'output' setting.
+func (a *app) outputFormat() (string, error) {
+	if a.output != "" {
+		if err := checkOutput(a.output); err != nil {
+			return "", withExit(exitUsage, err)
+		}
+		return a.output, nil
+	}
+	return config.SettingString("output")
+}
+
+// outputFormats lists the values accepted by --output.
+var outputFormats = format.Formats
+
+func checkOutput(value string) error {
+	for _, f := range outputFormats {
+		if value == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q (supported: %s)", value, strings.Join(outputFormats, ", "))
+}
+
+// color reports whether ANSI colors may be written to stdout.
+func (a *app) color() bool {
+	if a.noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := a.stdout.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// unicode reports whether the locale is UTF-8, so block and box characters
+// render. An unset locale is treated as C.
+func (a *app) unicode() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
+
+// termWidth is the width tables are fitted to: COLUMNS when set, else the
+// terminal's width, else 0 (unlimited) when stdout is not a terminal.
+func (a *app) termWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if f, ok := a.stdout.(*os.File); ok {
+		return ttyWidth(f)
+	}
+	return 0
+}
+
+// debugf prints to stderr with --verbose.
+func (a *app) debugf(format string, args ...any) {
+	if a.verbose {
+		fmt.Fprintf(a.stderr, "[debug] "+format+"\n", args...)
+	}
+}
+
+func (c *command) synopsis() string {
+	parts := []string{c.path()}
+	if len(c.Subcommands) > 0 {
+		if c.Run == nil {
+			parts = append(parts, "<command>")
+		} else {
+			parts = append(parts, "[command]")
+		}
+	}
+	parts = append(parts, "[flags]")
+	if c.Args != "" {
+		parts = append(parts, c.Args)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (a *app) printHelp(c *command) {
+	w := a.stdout
+	desc := c.Long
+	if desc == "" {
+		desc = c.Short
+	}
+	if desc != "" {
+		fmt.Fprintln(w, desc)
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintf(w, "  %s\n", c.synopsis())
+
+	var subs []*command
+	for _, sub := range c.Subcommands {
+		if !sub.Hidden {
+			subs = append(subs, sub)
+		}
+	}
+	if len(subs) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Commands:")
+		width := 0
+		for _, sub := range subs {
+			width = max(width, len(sub.Name))
+		}
+		for _, sub := range subs {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, sub.Name, sub.Short)
+		}
+	}
+
+	fs := a.newFlagSet(c)
+	if local := flagLines(fs, func(name string) bool { return !isGlobalFlag(name) }); len(local) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Flags:")
+		fmt.Fprintln(w, strings.Join(local, "\n"))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	fmt.Fprintln(w, strings.Join(flagLines(fs, isGlobalFlag), "\n"))
+
+	if len(c.Examples) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Examples:")
+		for _, ex := range c.Examples {
+			fmt.Fprintf(w, "  %s\n", ex)
+		}
+	}
+	if len(subs) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Run '%s <command> --help' for more about a command.\n", c.path())
+	}
+}
+
+func flagLines(fs *flag.FlagSet, include func(string) bool) []string {
+	type entry struct{ name, usage string }
+	var entries []entry
+	fs.VisitAll(func(f *flag.Flag) {
+		if !include(f.Name) {
+			return
+		}
+		name := "--" + f.Name
+		if kind, _ := flag.UnquoteUsage(f); kind != "" {
+			name += " " + kind
+		}
+		usage := f.Usage
+		if f.DefValue != "" && f.DefValue != "0" && f.DefValue != "false" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		entries = append(entries, entry{name, usage})
+	})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.name))
+	}
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, fmt.Sprintf("  %-*s  %s", width, e.name, e.usage))
+	}
+	return lines
+}
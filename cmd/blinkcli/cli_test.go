@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+
+	"blinkcli/internal/config"
+)
+
+func testTree(ran *[]string) *command {
+	var count int
+	return &command{
+		Name: "blinkcli",
+		Subcommands: []*command{
+			{
+				Name: "group",
+				Subcommands: []*command{
+					{
+						Name:    "leaf",
+						Args:    "<name>",
+						Short:   "A leaf",
+						MinArgs: 1,
+						MaxArgs: 1,
+						Flags: func(fs *flag.FlagSet) {
+							fs.IntVar(&count, "count", 0, "how many")
+						},
+						Run: func(a *app, args []string) error {
+							*ran = append(*ran, args[0], a.profile)
+							if count > 0 {
+								*ran = append(*ran, "count")
+							}
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name: "fail",
+				Run: func(a *app, args []string) error {
+					return withExit(exitAuth, errors.New("nope"))
+				},
+			},
+		},
+	}
+}
+
+// withProfiles points the config at a temporary directory holding the named
+// profiles.
+func withProfiles(t *testing.T, names ...string) {
+	t.Helper()
+	t.Setenv(config.ConfigDirEnv, t.TempDir())
+	t.Setenv(config.ProfileEnv, "")
+	for _, name := range names {
+		if err := config.AddProfile(name); err != nil {
+			t.Fatalf("add profile: %v", err)
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	withProfiles(t, "work")
+	var ran []string
+	var out, errOut bytes.Buffer
+	a := &app{stdout: &out, stderr: &errOut}
+
+	code := a.execute(testTree(&ran), []string{"group", "leaf", "--count", "2", "--profile", "work", "x"})
+	if code != exitOK {
+		t.Fatalf("expected exit 0, got %d (%s)", code, errOut.String())
+	}
+	if strings.Join(ran, ",") != "x,work,count" {
+		t.Fatalf("unexpected run record %v", ran)
+	}
+}
+
+func TestDispatchInterspersedFlags(t *testing.T) {
+	var ran []string
+	var out, errOut bytes.Buffer
+	a := &app{stdout: &out, stderr: &errOut}
+
+	code := a.execute(testTree(&ran), []string{"group", "leaf", "x", "--count", "2"})
+	if code != exitOK {
+		t.Fatalf("expected exit 0, got %d (%s)", code, errOut.String())
+	}
+	if strings.Join(ran, ",") != "x,,count" {
+		t.Fatalf("unexpected run record %v", ran)
+	}
+
+	ran = nil
+	code = a.execute(testTree(&ran), []string{"group", "leaf", "--", "--count"})
+	if code != exitOK || strings.Join(ran, ",") != "--count," {
+		t.Fatalf("expected '--' to end flags, got %v (exit %d)", ran, code)
+	}
+}
+
+func TestDispatchExitCodes(t *testing.T) {
+	withProfiles(t)
+	cases := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"bogus"}, exitUsage},
+		{[]string{"group"}, exitUsage},
+		{[]string{"group", "leaf"}, exitUsage},
+		{[]string{"group", "leaf", "--bogus", "x"}, exitUsage},
+		{[]string{"fail"}, exitAuth},
+		{[]string{"--profile", "wrok", "fail"}, exitUsage},
+		{[]string{"group", "leaf", "--help"}, exitOK},
+	}
+	for _, tc := range cases {
+		var ran []string
+		var out, errOut bytes.Buffer
+		a := &app{stdout: &out, stderr: &errOut}
+		if code := a.execute(testTree(&ran), tc.args); code != tc.code {
+			t.Fatalf("%v: expected exit %d, got %d (%s)", tc.args, tc.code, code, errOut.String())
+		}
+	}
+}
+
+func TestHelpListsFlags(t *testing.T) {
+	var ran []string
+	var out bytes.Buffer
+	a := &app{stdout: &out, stderr: &out}
+	a.execute(testTree(&ran), []string{"group", "leaf", "-h"})
+
+	help := out.String()
+	for _, want := range []string{"blinkcli group leaf [flags] <name>", "--count int", "Global flags:", "--profile string"} {
+		if !strings.Contains(help, want) {
+			t.Fatalf("expected help to contain %q, got:\n%s", want, help)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+	"blinkcli/internal/query"
+)
+
+const bashCompletion = `# bash completion for blinkcli
+_blinkcli() {
+    local line cur="" prefix words
+    # COMP_WORDS splits --flag=value at "=" (see COMP_WORDBREAKS), so split
+    # the line up to the cursor on whitespace instead.
+    line="${COMP_LINE:0:COMP_POINT}"
+    read -ra words <<< "$line"
+    if [[ "$line" != *[[:space:]] ]]; then
+        cur="${words[${#words[@]}-1]}"
+        unset "words[${#words[@]}-1]"
+    fi
+    # Bash replaces only the part of cur after the last break, so strip
+    # the rest from the candidates.
+    prefix="${cur%"${COMP_WORDS[COMP_CWORD]}"}"
+    [[ "$prefix" == "$cur" ]] && prefix=""
+    COMPREPLY=()
+    while IFS= read -r line; do
+        COMPREPLY+=("$(printf '%q' "${line#"$prefix"}")")
+    done < <(blinkcli __complete "${words[@]:1}" "$cur" 2>/dev/null)
+}
+complete -F _blinkcli blinkcli
+`
+
+const zshCompletion = `#compdef blinkcli
+# zsh completion for blinkcli
+_blinkcli() {
+    local -a candidates
+    candidates=(${(f)"$(blinkcli __complete "${(@)words[2,CURRENT-1]}" "${words[CURRENT]}" 2>/dev/null)"})
+    compadd -a candidates
+}
+compdef _blinkcli blinkcli
+`
+
+const fishCompletion = `# fish completion for blinkcli
+function __blinkcli_complete
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    blinkcli __complete $tokens (commandline -ct) 2>/dev/null
+end
+complete -c blinkcli -f -a '(__blinkcli_complete)'
+`
+
+var completionScripts = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+func completionCmd() *command {
+	return &command{
+		Name:  "completion",
+		Args:  "bash|zsh|fish",
+		Short: "Print a shell completion script",
+		Long: `Print a shell completion script.
+
+Completion covers commands, flags, profile and location names, setting keys,
+and order IDs and item names from the local store.`,
+		Examples: []string{
+			"source <(blinkcli completion bash)        # ~/.bashrc",
+			"source <(blinkcli completion zsh)         # ~/.zshrc, after compinit",
+			"blinkcli completion fish > ~/.config/fish/completions/blinkcli.fish",
+		},
+		MinArgs: 1,
+		MaxArgs: 1,
+		ValidArgs: func(a *app) []string {
+			return []string{"bash", "fish", "zsh"}
+		},
+		Run: func(a *app, args []string) error {
+			script, ok := completionScripts[args[0]]
+			if !ok {
+				return usageErrorf("unsupported shell %q (choose bash, zsh or fish)", args[0])
+			}
+			fmt.Fprint(a.stdout, script)
+			return nil
+		},
+	}
+}
+
+// completeCmd is called by the completion scripts with the words typed so
+// far; the last one is the word being completed.
+func completeCmd(root *command) *command {
+	return &command{
+		Name:    "__complete",
+		Hidden:  true,
+		RawArgs: true,
+		Run: func(a *app, args []string) error {
+			if len(args) == 0 {
+				args = []string{""}
+			}
+			for _, candidate := range a.complete(root, args[:len(args)-1], args[len(args)-1]) {
+				fmt.Fprintln(a.stdout, candidate)
+			}
+			return nil
+		},
+	}
+}
+
+// complete returns the candidates for toComplete after the words typed so far.
+func (a *app) complete(root *command, words []string, toComplete string) []string {
+	cmd := root
+	var (
+		pendingFlag string
+		positional  int
+	)
+	for _, word := range words {
+		if pendingFlag != "" {
+			a.completeFlag(pendingFlag, word)
+			pendingFlag = ""
+			continue
+		}
+		if strings.HasPrefix(word, "-") {
+			name := strings.TrimLeft(word, "-")
+			if name, value, ok := strings.Cut(name, "="); ok {
+				a.completeFlag(name, value)
+				continue
+			}
+			if f := a.newFlagSet(cmd).Lookup(name); f != nil && !isBoolFlag(f) {
+				pendingFlag = name
+			}
+			continue
+		}
+		if sub := cmd.find(word); sub != nil && positional == 0 {
+			cmd = sub
+			continue
+		}
+		positional++
+	}
+
+	if pendingFlag != "" {
+		return filterValues(a.flagValues(cmd, pendingFlag), toComplete)
+	}
+	if strings.HasPrefix(toComplete, "-") {
+		if name, value, ok := strings.Cut(strings.TrimLeft(toComplete, "-"), "="); ok {
+			prefix := toComplete[:len(toComplete)-len(value)]
+			var out []string
+			for _, v := range filterValues(a.flagValues(cmd, name), value) {
+				out = append(out, prefix+v)
+			}
+			return out
+		}
+		var names []string
+		a.newFlagSet(cmd).VisitAll(func(f *flag.Flag) {
+			names = append(names, "--"+f.Name)
+		})
+		sort.Strings(names)
+		return filterPrefix(names, toComplete)
+	}
+
+	if positional > 0 {
+		return nil
+	}
+	var candidates []string
+	for _, sub := range cmd.Subcommands {
+		if !sub.Hidden {
+			candidates = append(candidates, sub.Name)
+		}
+	}
+	if cmd.ValidArgs != nil {
+		candidates = append(candidates, cmd.ValidArgs(a)...)
+	}
+	return filterPrefix(candidates, toComplete)
+}
+
+// completeFlag applies a flag already on the command line that changes
+// where candidates come from, so --profile work completes work's order IDs.
+func (a *app) completeFlag(name, value string) {
+	if name == "profile" {
+		// An unknown profile keeps the active one; completion stays quiet.
+		_ = config.SetProfile(value)
+	}
+}
+
+func (a *app) flagValues(cmd *command, name string) []string {
+	if values, ok := cmd.FlagValues[name]; ok {
+		return values(a)
+	}
+	switch name {
+	case "profile":
+		return completeProfiles(a)
+	case "output":
+		return outputFormats
+	}
+	return nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// filterValues completes the last element of a comma-separated flag value.
+func filterValues(values []string, toComplete string) []string {
+	head := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		head, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	out := filterPrefix(values, toComplete)
+	for i := range out {
+		out[i] = head + out[i]
+	}
+	return out
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	var out []string
+	lower := strings.ToLower(prefix)
+	for _, v := range values {
+		if strings.HasPrefix(strings.ToLower(v), lower) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func completeProfiles(a *app) []string {
+	profiles, _ := config.ListProfiles()
+	return profiles
+}
+
+func completeLocations(a *app) []string {
+	cfg, err := config.LoadFile()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Locations))
+	for _, loc := range cfg.Locations {
+		names = append(names, loc.Name)
+	}
+	return names
+}
+
+func completeSettingKeys(a *app) []string {
+	return config.SettingKeys()
+}
+
+func completeBackends(a *app) []string {
+	return config.SecretBackends()
+}
+
+func completeSortKeys(a *app) []string {
+	keys := make([]string, 0, 2*len(format.SortKeys))
+	for _, key := range format.SortKeys {
+		keys = append(keys, key, "-"+key)
+	}
+	return keys
+}
+
+// completeOrderIDs lists stored order IDs, newest first.
+func completeOrderIDs(a *app) []string {
+	orders, err := loadOrders(false)
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		if order.ID != "" {
+			ids = append(ids, order.ID)
+		}
+	}
+	return ids
+}
+
+// completeStatuses lists the statuses seen in the store.
+func completeStatuses(a *app) []string {
+	orders, err := loadOrders(false)
+	if err != nil {
+		return nil
+	}
+	return query.Statuses(orders)
+}
+
+// completeItems lists distinct stored item names, sorted.
+func completeItems(a *app) []string {
+	orders, err := loadOrders(false)
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var items []string
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if !seen[item] {
+				seen[item] = true
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Strings(items)
+	return items
+}
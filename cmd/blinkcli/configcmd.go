@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"blinkcli/internal/config"
+)
+
+func configCmd() *command {
+	return &command{
+		Name:  "config",
+		Short: "Read and change settings",
+		Long: `Read and change settings.
+
+Settings live in config.json and can be overridden by BLINKCLI_* environment
+variables; command flags override both.`,
+		Examples: []string{
+			"blinkcli config list",
+			"blinkcli config set sync.pages 5",
+			"blinkcli config set timezone Asia/Kolkata",
+		},
+		Subcommands: []*command{
+			{
+				Name:  "list",
+				Short: "List settings with their values and sources",
+				Run: func(a *app, args []string) error {
+					settings, err := config.Settings()
+					if err != nil {
+						return err
+					}
+					for _, setting := range settings {
+						fmt.Fprintf(a.stdout, "%s=%s (%s)\n", setting.Key, setting.Value, setting.Source)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "get",
+				Args:      "<key>",
+				Short:     "Print a setting's value",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeSettingKeys,
+				Run: func(a *app, args []string) error {
+					setting, err := config.GetSetting(args[0])
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(a.stdout, setting.Value)
+					return nil
+				},
+			},
+			{
+				Name:      "set",
+				Args:      "<key> <value>",
+				Short:     "Store a setting in config.json",
+				MinArgs:   2,
+				MaxArgs:   2,
+				ValidArgs: completeSettingKeys,
+				Run: func(a *app, args []string) error {
+					return config.SetSetting(args[0], args[1])
+				},
+			},
+			{
+				Name:      "unset",
+				Args:      "<key>",
+				Short:     "Remove a setting from config.json",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeSettingKeys,
+				Run: func(a *app, args []string) error {
+					return config.SetSetting(args[0], "")
+				},
+			},
+			configMigrateSecretsCmd(),
+		},
+	}
+}
+
+func configMigrateSecretsCmd() *command {
+	var backend string
+	return &command{
+		Name:  "migrate-secrets",
+		Short: "Move the stored session to another secret backend",
+		Examples: []string{
+			"blinkcli config migrate-secrets --backend keyring",
+			"BLINKCLI_PASSPHRASE=... blinkcli config migrate-secrets --backend encrypted-file",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&backend, "backend", "", "target backend: "+strings.Join(config.SecretBackends(), ", "))
+		},
+		FlagValues: map[string]func(a *app) []string{"backend": completeBackends},
+		Run: func(a *app, args []string) error {
+			if backend == "" {
+				return usageErrorf("--backend is required")
+			}
+			if err := config.MigrateSecrets(backend); err != nil {
+				return err
+			}
+			fmt.Fprintf(a.stdout, "Session secrets now stored in %s.\n", backend)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+	"blinkcli/internal/query"
+)
+
+// filterFlags holds the order filters shared by 'orders' and 'search'.
+type filterFlags struct {
+	since, until string
+	min, max     int
+	status       string
+	item         string
+	location     string
+	limit        int
+}
+
+func (f *filterFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.since, "since", "", "orders on or after a date (2024-03-01, 2024-03) or duration ago (30d, 2w, 6m, 1y)")
+	fs.StringVar(&f.until, "until", "", "orders before the end of a date, or before a duration ago")
+	fs.IntVar(&f.min, "min", 0, "minimum amount in rupees")
+	fs.IntVar(&f.max, "max", 0, "maximum amount in rupees")
+	fs.StringVar(&f.status, "status", "", "order status, e.g. delivered")
+	fs.StringVar(&f.item, "item", "", "item name substring or regular expression (case-insensitive)")
+	fs.StringVar(&f.location, "location", "", "saved location the order was synced under")
+	fs.IntVar(&f.limit, "limit", 0, "show at most this many orders")
+}
+
+// flagValues completes the filter flags' values.
+func (f *filterFlags) flagValues() map[string]func(a *app) []string {
+	return map[string]func(a *app) []string{
+		"status":   completeStatuses,
+		"item":     completeItems,
+		"location": completeLocations,
+	}
+}
+
+// filter builds the query filter; bad values are usage errors.
+func (f *filterFlags) filter(now time.Time) (query.Filter, error) {
+	if f.min < 0 || f.max < 0 || f.limit < 0 {
+		return query.Filter{}, usageErrorf("--min, --max and --limit cannot be negative")
+	}
+	if f.max > 0 && f.min > f.max {
+		return query.Filter{}, usageErrorf("--min is larger than --max")
+	}
+	filter := query.Filter{
+		Min:      f.min,
+		Max:      f.max,
+		Status:   f.status,
+		Location: f.location,
+	}
+	tz, err := config.TimeZone()
+	if err != nil {
+		return query.Filter{}, err
+	}
+	if f.since != "" {
+		if filter.Since, err = query.ParseTime(f.since, now, tz, false); err != nil {
+			return query.Filter{}, withExit(exitUsage, err)
+		}
+	}
+	if f.until != "" {
+		if filter.Until, err = query.ParseTime(f.until, now, tz, true); err != nil {
+			return query.Filter{}, withExit(exitUsage, err)
+		}
+	}
+	if f.item != "" {
+		filter.Item = query.ItemPattern(f.item)
+	}
+	return filter, nil
+}
+
+// truncate applies --limit.
+func (f *filterFlags) truncate(orders []blink.Order) []blink.Order {
+	if f.limit > 0 && len(orders) > f.limit {
+		return orders[:f.limit]
+	}
+	return orders
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"blinkcli/internal/config"
+)
+
+func locationCmd() *command {
+	return &command{
+		Name:  "location",
+		Short: "Manage saved delivery locations",
+		Long: `Manage saved delivery locations.
+
+The address selected at login is saved automatically. Commands that call
+Blinkit accept --location to pick one; otherwise the default is used.`,
+		Examples: []string{
+			"blinkcli location add --name office --lat 12.9352 --lon 77.6245",
+			"blinkcli location use office",
+			"blinkcli sync --location home",
+		},
+		Subcommands: []*command{
+			{
+				Name:  "list",
+				Short: "List saved locations, marking the default",
+				Run: func(a *app, args []string) error {
+					cfg, err := config.LoadFile()
+					if err != nil {
+						return err
+					}
+					if len(cfg.Locations) == 0 {
+						fmt.Fprintln(a.stdout, "No saved locations. Log in or run 'blinkcli location add'.")
+						return nil
+					}
+					for _, loc := range cfg.Locations {
+						marker := " "
+						if strings.EqualFold(loc.Name, cfg.DefaultLocation) {
+							marker = "*"
+						}
+						place := loc.Locality
+						if loc.Landmark != "" {
+							place = strings.TrimSpace(loc.Landmark + ", " + place)
+						}
+						fmt.Fprintf(a.stdout, "%s %s (%.6f, %.6f) %s\n", marker, loc.Name, loc.Lat, loc.Lon, place)
+					}
+					return nil
+				},
+			},
+			locationAddCmd(),
+			{
+				Name:      "remove",
+				Args:      "<name>",
+				Short:     "Delete a saved location",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeLocations,
+				Run: func(a *app, args []string) error {
+					return updateConfig(func(cfg *config.Config) error {
+						if !cfg.RemoveLocation(args[0]) {
+							return fmt.Errorf("unknown location %q", args[0])
+						}
+						fmt.Fprintf(a.stdout, "Location %s removed.\n", args[0])
+						return nil
+					})
+				},
+			},
+			{
+				Name:      "use",
+				Args:      "<name>",
+				Short:     "Make a location the default",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeLocations,
+				Run: func(a *app, args []string) error {
+					return updateConfig(func(cfg *config.Config) error {
+						loc, ok := cfg.FindLocation(args[0])
+						if !ok {
+							return fmt.Errorf("unknown location %q", args[0])
+						}
+						cfg.DefaultLocation = loc.Name
+						fmt.Fprintf(a.stdout, "Default location is now %s.\n", loc.Name)
+						return nil
+					})
+				},
+			},
+		},
+	}
+}
+
+func locationAddCmd() *command {
+	var (
+		name     string
+		lat      float64
+		lon      float64
+		locality string
+		landmark string
+	)
+	return &command{
+		Name:  "add",
+		Short: "Save a location by coordinates",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&name, "name", "", "location name, e.g. home or office")
+			fs.Float64Var(&lat, "lat", 0, "latitude")
+			fs.Float64Var(&lon, "lon", 0, "longitude")
+			fs.StringVar(&locality, "locality", "", "locality shown by Blinkit (optional)")
+			fs.StringVar(&landmark, "landmark", "", "landmark (optional)")
+		},
+		Run: func(a *app, args []string) error {
+			if name == "" || lat == 0 || lon == 0 {
+				return usageErrorf("--name, --lat and --lon are required")
+			}
+			return updateConfig(func(cfg *config.Config) error {
+				cfg.SetLocation(config.Location{Name: name, Lat: lat, Lon: lon, Locality: locality, Landmark: landmark})
+				if cfg.DefaultLocation == "" {
+					cfg.DefaultLocation = name
+				}
+				fmt.Fprintf(a.stdout, "Location %s saved.\n", name)
+				return nil
+			})
+		},
+	}
+}
+
+// updateConfig applies update to the active profile's config.json. The
+// session is not read or written, so edits work without the secret backend.
+func updateConfig(update func(cfg *config.Config) error) error {
+	return config.Update(update)
+}
+
+// saveSessionLocation records the address selected during login as a named
+// location, making it the default if there is none.
+func saveSessionLocation(cfg *config.Config, name string) {
+	if cfg.Session == nil || cfg.Session.Lat == 0 || cfg.Session.Lon == 0 || name == "" {
+		return
+	}
+	cfg.SetLocation(config.SessionLocation(name, cfg.Session))
+	if cfg.DefaultLocation == "" {
+		cfg.DefaultLocation = name
+	}
+}
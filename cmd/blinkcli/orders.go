@@ -0,0 +1,212 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+	"blinkcli/internal/store"
+)
+
+const noOrdersMsg = "No orders stored yet. Run 'blinkcli sync'."
+
+func ordersCmd() *command {
+	var (
+		columns, sortKey string
+		tmpl             templateFlags
+		filters          filterFlags
+	)
+	return &command{
+		Name:        "orders",
+		Subcommands: []*command{ordersShowCmd()},
+		Short:       "List and filter stored orders",
+		Long: `List and filter stored orders.
+
+--since and --until take dates (2024-03-01, 2024-03, 2024) or durations back
+from now (30d, 2w, 6m, 1y); --until includes the whole day, month or year
+given. Filters combine; --limit applies after --sort.
+
+The table fits the terminal width, wrapping item lists. --columns picks and
+orders the columns of the table, csv, tsv and markdown output; --sort orders
+the rows for every format.
+
+--output selects table, json, ndjson, csv, tsv or markdown; the json schema is
+documented in docs/output-schema.md.
+
+--template renders each order (a blink.Order: .ID, .Date, .AmountRupees,
+.Status, .Title, .Items, .Location) with Go's text/template. Helpers:
+rupees, date, join and truncate.`,
+		Examples: []string{
+			"blinkcli orders",
+			"blinkcli orders --columns date,amount,status,items,id --sort -amount",
+			"blinkcli orders --since 30d --min 500 --item 'milk|curd'",
+			"blinkcli orders --since 2024-01 --until 2024-03 --status delivered --limit 10",
+			"blinkcli orders show 123456789",
+			"blinkcli orders --output json | jq '.[0]'",
+			"blinkcli orders --output csv > orders.csv",
+			`blinkcli orders --template '{{.Date | date "02 Jan"}} {{.AmountRupees | rupees}} {{.Items | join ", " | truncate 40}}'`,
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&columns, "columns", "", "comma-separated columns: "+strings.Join(format.OrderColumns, ", "))
+			fs.StringVar(&sortKey, "sort", "", "sort by "+strings.Join(format.SortKeys, ", ")+"; prefix - for descending")
+			tmpl.register(fs)
+			filters.register(fs)
+		},
+		FlagValues: func() map[string]func(a *app) []string {
+			values := filters.flagValues()
+			values["columns"] = func(a *app) []string { return format.OrderColumns }
+			values["sort"] = completeSortKeys
+			return values
+		}(),
+		Run: func(a *app, args []string) error {
+			t, err := tmpl.parse(a)
+			if err != nil {
+				return err
+			}
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			opts := format.OrdersOptions{Width: a.termWidth()}
+			if columns != "" {
+				if opts.Columns, err = format.ParseColumns(columns); err != nil {
+					return withExit(exitUsage, err)
+				}
+			}
+			filter, err := filters.filter(time.Now())
+			if err != nil {
+				return err
+			}
+			orders, err := loadOrders(false)
+			if err != nil {
+				return err
+			}
+			stored := len(orders)
+			orders = filter.Apply(orders)
+			if sortKey != "" {
+				if err := format.SortOrders(orders, sortKey); err != nil {
+					return withExit(exitUsage, err)
+				}
+			}
+			orders = filters.truncate(orders)
+			if t != nil {
+				for _, order := range orders {
+					if err := format.ExecuteTemplate(a.stdout, t, order); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			if len(orders) == 0 && output == format.Table {
+				if stored > 0 {
+					fmt.Fprintln(a.stdout, "No orders match the filters.")
+				} else {
+					fmt.Fprintln(a.stdout, noOrdersMsg)
+				}
+				return nil
+			}
+			return format.Write(a.stdout, output, format.Orders(orders, opts))
+		},
+	}
+}
+
+func ordersShowCmd() *command {
+	return &command{
+		Name:  "show",
+		Args:  "<id>",
+		Short: "Show every stored field of one order",
+		Long: `Show every stored field of one order.
+
+Prints the full item list, when the order was synced, and links to open it
+in the Blinkit app or on blinkit.com. The order is found by order ID or cart
+ID. With --output json the fields are those of docs/output-schema.md.`,
+		Examples: []string{
+			"blinkcli orders show 123456789",
+			"blinkcli orders show 123456789 --output json",
+		},
+		MinArgs:   1,
+		MaxArgs:   1,
+		ValidArgs: completeOrderIDs,
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			orders, err := loadOrders(false)
+			if err != nil {
+				return err
+			}
+			order, ok := findOrder(orders, args[0])
+			if !ok {
+				return fmt.Errorf("no stored order %q (see 'blinkcli orders')", args[0])
+			}
+			return format.Write(a.stdout, output, format.OrderDetail(order))
+		},
+	}
+}
+
+// findOrder looks an order up by ID, then by cart ID.
+func findOrder(orders []blink.Order, id string) (blink.Order, bool) {
+	for _, order := range orders {
+		if order.ID == id {
+			return order, true
+		}
+	}
+	for _, order := range orders {
+		if order.CartID != "" && order.CartID == id {
+			return order, true
+		}
+	}
+	return blink.Order{}, false
+}
+
+// loadOrders reads the active profile's store, or every profile's when
+// allProfiles is set, with dates in the configured time zone.
+func loadOrders(allProfiles bool) ([]blink.Order, error) {
+	profiles := []string{config.ActiveProfile()}
+	if allProfiles {
+		var err error
+		if profiles, err = config.ListProfiles(); err != nil {
+			return nil, err
+		}
+	}
+
+	var orders []blink.Order
+	for _, profile := range profiles {
+		st, err := store.ForProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		loaded, err := st.Load()
+		if err != nil {
+			return nil, err
+		}
+		if len(profiles) == 1 {
+			orders = loaded
+			break
+		}
+		orders = store.CombineOrders(orders, loaded)
+	}
+
+	tz, err := config.TimeZone()
+	if err != nil {
+		return nil, err
+	}
+	inTimeZone(orders, tz)
+	return orders, nil
+}
+
+// inTimeZone converts order and sync dates for display and bucketing.
+func inTimeZone(orders []blink.Order, loc *time.Location) {
+	for i := range orders {
+		for _, t := range []*time.Time{&orders[i].Date, &orders[i].FirstSyncedAt, &orders[i].LastSyncedAt} {
+			if !t.IsZero() {
+				*t = t.In(loc)
+			}
+		}
+	}
+}
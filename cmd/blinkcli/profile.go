@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+
+	"blinkcli/internal/config"
+)
+
+func profileCmd() *command {
+	return &command{
+		Name:  "profile",
+		Short: "Manage named profiles for several Blinkit accounts",
+		Long: `Manage named profiles for several Blinkit accounts.
+
+Each profile has its own session and order store. The active profile comes
+from --profile, then BLINKCLI_PROFILE, then 'profile use', else "default".`,
+		Examples: []string{
+			"blinkcli profile add work",
+			"blinkcli --profile work auth login",
+			"blinkcli profile use work",
+		},
+		Subcommands: []*command{
+			{
+				Name:  "list",
+				Short: "List profiles, marking the active one",
+				Run: func(a *app, args []string) error {
+					profiles, err := config.ListProfiles()
+					if err != nil {
+						return err
+					}
+					active := config.ActiveProfile()
+					for _, profile := range profiles {
+						marker := " "
+						if profile == active {
+							marker = "*"
+						}
+						fmt.Fprintf(a.stdout, "%s %s\n", marker, profile)
+					}
+					return nil
+				},
+			},
+			{
+				Name:    "add",
+				Args:    "<name>",
+				Short:   "Create a profile",
+				MinArgs: 1,
+				MaxArgs: 1,
+				Run: func(a *app, args []string) error {
+					if err := config.AddProfile(args[0]); err != nil {
+						return err
+					}
+					fmt.Fprintf(a.stdout, "Profile %s created. Log in with 'blinkcli --profile %s auth login'.\n", args[0], args[0])
+					return nil
+				},
+			},
+			{
+				Name:      "remove",
+				Args:      "<name>",
+				Short:     "Delete a profile with its session and orders",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeProfiles,
+				Run: func(a *app, args []string) error {
+					if err := config.RemoveProfile(args[0]); err != nil {
+						return err
+					}
+					fmt.Fprintf(a.stdout, "Profile %s removed.\n", args[0])
+					return nil
+				},
+			},
+			{
+				Name:      "use",
+				Args:      "<name>",
+				Short:     "Make a profile the default for later commands",
+				MinArgs:   1,
+				MaxArgs:   1,
+				ValidArgs: completeProfiles,
+				Run: func(a *app, args []string) error {
+					if err := config.UseProfile(args[0]); err != nil {
+						return err
+					}
+					fmt.Fprintf(a.stdout, "Now using profile %s.\n", args[0])
+					return nil
+				},
+			},
+		},
+	}
+}
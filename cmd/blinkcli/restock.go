@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+	"blinkcli/internal/stats"
+)
+
+func restockCmd() *command {
+	var (
+		allProfiles   bool
+		location      string
+		within        int
+		minConfidence float64
+		list          bool
+	)
+	return &command{
+		Name:  "restock",
+		Short: "List products that are due to be bought again",
+		Long: `List products that are due to be bought again.
+
+For every product bought on at least two days (names normalized as in 'stats
+items'), the typical repurchase interval is the median gap between
+purchases. Products whose next purchase falls within --within days, or is
+already past, are listed most overdue first. Confidence (0-100%) grows with
+the number of purchases and how regular they are, and fades for items long
+overdue, which were probably dropped.
+
+--list prints a Markdown checklist to paste into notes or chat.`,
+		Examples: []string{
+			"blinkcli restock",
+			"blinkcli restock --within 3 --min-confidence 0.5",
+			"blinkcli restock --list > shopping.md",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "use orders from all profiles")
+			fs.StringVar(&location, "location", "", "only use orders synced under this saved location")
+			fs.IntVar(&within, "within", 7, "include items due within this many days")
+			fs.Float64Var(&minConfidence, "min-confidence", 0.1, "hide predictions below this confidence (0-1)")
+			fs.BoolVar(&list, "list", false, "print a shopping list checklist")
+		},
+		FlagValues: map[string]func(a *app) []string{"location": completeLocations},
+		Run: func(a *app, args []string) error {
+			if within < 0 || minConfidence < 0 || minConfidence > 1 {
+				return usageErrorf("--within must be positive and --min-confidence between 0 and 1")
+			}
+			if list && a.output != "" {
+				return usageErrorf("--output cannot be combined with --list")
+			}
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && list {
+				// Keep prose out of an exported shopping list.
+				fmt.Fprintln(a.stderr, noOrdersMsg)
+				return nil
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			items := stats.Restock(orders, time.Now().In(tz), within, minConfidence)
+			if list {
+				if len(items) > 0 {
+					fmt.Fprintln(a.stdout, stats.ShoppingList(items))
+				}
+				return nil
+			}
+			if len(items) == 0 && output == format.Table {
+				fmt.Fprintf(a.stdout, "Nothing due in the next %d days.\n", within)
+				return nil
+			}
+			return format.Write(a.stdout, output, stats.RestockOutput(items, a.termWidth()))
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+	"blinkcli/internal/query"
+)
+
+// ANSI codes for highlighted matches.
+const (
+	highlightOn  = "\x1b[1;33m"
+	highlightOff = "\x1b[0m"
+)
+
+func searchCmd() *command {
+	var filters filterFlags
+	return &command{
+		Name:  "search",
+		Args:  "<query...>",
+		Short: "Find orders by item name",
+		Long: `Find orders by item name.
+
+Matching is case-insensitive and fuzzy: every word of the query must appear
+in an item name, as a substring or as its letters in order ("amltz" finds
+"Amul Taaza"). Orders are listed best match first with the matched letters
+highlighted. The filters of 'blinkcli orders' narrow the search; other
+--output formats list the matching orders as 'blinkcli orders' does.`,
+		Examples: []string{
+			"blinkcli search milk",
+			"blinkcli search amul butter --since 6m",
+			"blinkcli search eggs --output json",
+		},
+		MinArgs:    1,
+		MaxArgs:    -1,
+		ValidArgs:  completeItems,
+		Flags:      filters.register,
+		FlagValues: filters.flagValues(),
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			filter, err := filters.filter(time.Now())
+			if err != nil {
+				return err
+			}
+			orders, err := loadOrders(false)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			results := query.Search(filter.Apply(orders), strings.Join(args, " "))
+			if filters.limit > 0 && len(results) > filters.limit {
+				results = results[:filters.limit]
+			}
+
+			if output != format.Table {
+				matched := make([]blink.Order, len(results))
+				for i, r := range results {
+					matched[i] = r.Order
+				}
+				return format.Write(a.stdout, output, format.Orders(matched, format.OrdersOptions{}))
+			}
+			if len(results) == 0 {
+				fmt.Fprintf(a.stdout, "No items match %q.\n", strings.Join(args, " "))
+				return nil
+			}
+			open, close := "[", "]"
+			if a.color() {
+				open, close = highlightOn, highlightOff
+			}
+			fmt.Fprint(a.stdout, formatResults(results, open, close))
+			return nil
+		},
+	}
+}
+
+// formatResults lists each order with its matching items indented below.
+func formatResults(results []query.Result, open, close string) string {
+	var b strings.Builder
+	for _, r := range results {
+		date := format.RelativeDate(r.Order.Date)
+		if date == "" {
+			date = "unknown date"
+		}
+		fmt.Fprintf(&b, "%s  %s  %s\n", date, format.Rupees(r.Order.AmountRupees), r.Order.ID)
+		for _, m := range r.Matches {
+			fmt.Fprintf(&b, "  %s\n", query.Highlight(m, open, close))
+		}
+		if others := len(r.Order.Items) - len(r.Matches); others > 0 {
+			fmt.Fprintf(&b, "  (+%d other items)\n", others)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+	"blinkcli/internal/query"
+	"blinkcli/internal/stats"
+)
+
+func statsCmd() *command {
+	var (
+		allProfiles bool
+		location    string
+		tmpl        templateFlags
+		opts        stats.SummaryOptions
+		smallOrder  int
+	)
+	return &command{
+		Name:        "stats",
+		Subcommands: []*command{statsItemsCmd(), statsHeatmapCmd(), statsCompareCmd(), statsPairsCmd(), statsForecastCmd()},
+		Short:       "Summarize stored orders by month, year, category and sync location",
+		Long: `Summarize stored orders by month, year, category and sync location.
+
+The sync location is the saved location that was active when 'blinkcli sync'
+first saw an order. Blinkit's order history doesn't say where an order was
+delivered, so it is sync context, not the delivery address; --location
+filters by it.
+
+Category spend is estimated by splitting each order's total evenly across its
+items; see 'blinkcli categories' for the rules.
+
+The order value section shows the average, median and 90th percentile order,
+items per order, a histogram of order values, the largest orders and the
+share of small orders, below --small-order rupees, that likely paid a
+delivery fee.
+
+--chart replaces the monthly list with sparklines of spend, order count and
+average order, then a bar per month of --metric (amount, count or avg),
+fitted to the terminal width, and draws each year's months as a sparkline
+next to its totals. Block characters are used on UTF-8 terminals, ASCII
+otherwise. It only changes the table format.
+
+--output selects table, json, ndjson, csv, tsv or markdown. Only the table
+format includes the generation time; the json schema is documented in
+docs/output-schema.md.
+
+--template renders the summary (.TotalOrders, .TotalAmount and the .Yearly,
+.Monthly, .Categories and .Locations buckets with .Label, .Count and .Amount) with Go's
+text/template. Helpers: rupees, date, join and truncate.`,
+		Examples: []string{
+			"blinkcli stats",
+			"blinkcli stats --all-profiles",
+			"blinkcli stats --location office",
+			"blinkcli stats --weekly",
+			"blinkcli stats --chart --metric count",
+			"blinkcli stats --output json",
+			`blinkcli stats --template '{{range .Yearly}}{{.Label}}: {{.Amount | rupees}}{{"\n"}}{{end}}'`,
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "aggregate orders across all profiles")
+			fs.StringVar(&location, "location", "", "only count orders synced under this saved location")
+			fs.BoolVar(&opts.Weekly, "weekly", false, "add ISO week buckets to the table")
+			fs.BoolVar(&opts.Daily, "daily", false, "add daily buckets to the table")
+			fs.IntVar(&smallOrder, "small-order", 0, "count orders below this many rupees as small (default: setting stats.small_order)")
+			fs.BoolVar(&opts.Chart, "chart", false, "draw monthly and yearly trends as charts")
+			fs.StringVar(&opts.Metric, "metric", stats.MetricAmount, "value charted per month: amount, count or avg")
+			tmpl.register(fs)
+		},
+		FlagValues: map[string]func(a *app) []string{
+			"location": completeLocations,
+			"metric":   func(a *app) []string { return stats.Metrics },
+		},
+		Run: func(a *app, args []string) error {
+			if !slices.Contains(stats.Metrics, opts.Metric) {
+				return usageErrorf("--metric must be one of %s", strings.Join(stats.Metrics, ", "))
+			}
+			if a.flagSet("metric") && !opts.Chart {
+				return usageErrorf("--metric needs --chart")
+			}
+			t, err := tmpl.parse(a)
+			if err != nil {
+				return err
+			}
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			categorizer, err := loadCategorizer()
+			if err != nil {
+				return err
+			}
+			if smallOrder, err = a.settingInt("small-order", "stats.small_order", smallOrder); err != nil {
+				return err
+			}
+			summary := stats.BuildSummary(orders)
+			summary.Categories = stats.CategoryBuckets(orders, categorizer.Category)
+			if smallOrder != stats.DefaultSmallOrder {
+				summary.Distribution = stats.BuildDistribution(orders, smallOrder)
+			}
+			opts.Unicode = a.unicode()
+			opts.Width = a.termWidth()
+			if t != nil {
+				return format.ExecuteTemplate(a.stdout, t, summary)
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			return format.Write(a.stdout, output, stats.SummaryOutput(summary, opts))
+		},
+	}
+}
+
+func statsItemsCmd() *command {
+	var (
+		allProfiles bool
+		location    string
+		top         int
+		by          string
+	)
+	return &command{
+		Name:  "items",
+		Short: "Rank products by how often they are bought",
+		Long: `Rank products by how often they are bought.
+
+Item names are normalized (case, spacing, pack sizes such as "500 ml" or
+"pack of 2") so variants of a product are counted together. For each product:
+the orders containing it, an estimated spend (each order's total split evenly
+across its items), first and last purchase, the average days between
+purchases, and orders in the last 3, 6 and 12 months with the change from the
+period before.`,
+		Examples: []string{
+			"blinkcli stats items",
+			"blinkcli stats items --top 10 --by spend",
+			"blinkcli stats items --output csv --top 0 > items.csv",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "aggregate orders across all profiles")
+			fs.StringVar(&location, "location", "", "only count orders synced under this saved location")
+			fs.IntVar(&top, "top", 20, "number of products to list (0 = all)")
+			fs.StringVar(&by, "by", stats.ByOrders, "rank by orders or spend")
+		},
+		FlagValues: map[string]func(a *app) []string{
+			"location": completeLocations,
+			"by":       func(a *app) []string { return []string{stats.ByOrders, stats.BySpend} },
+		},
+		Run: func(a *app, args []string) error {
+			if by != stats.ByOrders && by != stats.BySpend {
+				return usageErrorf("--by must be %s or %s", stats.ByOrders, stats.BySpend)
+			}
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			items := stats.ItemStats(orders, time.Now().In(tz), by, top)
+			return format.Write(a.stdout, output, stats.ItemsOutput(items, a.termWidth()))
+		},
+	}
+}
+
+func statsCompareCmd() *command {
+	var (
+		allProfiles bool
+		location    string
+		period      string
+		toDate      bool
+		top         int
+	)
+	return &command{
+		Name:  "compare",
+		Short: "Compare this month, quarter or year with earlier ones",
+		Long: `Compare this month, quarter or year with earlier ones.
+
+Shows orders, spend and average basket for the current period, the previous
+one and, for months and quarters, the same period last year, with the
+absolute and percentage change, followed by each period's top products.
+
+The current period is still running; --to-date cuts the earlier periods at
+the same point (e.g. the first 10 days of each month) so they compare like
+for like. Periods follow the timezone setting.`,
+		Examples: []string{
+			"blinkcli stats compare",
+			"blinkcli stats compare --period quarter --to-date",
+			"blinkcli stats compare --period year --output json",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "aggregate orders across all profiles")
+			fs.StringVar(&location, "location", "", "only count orders synced under this saved location")
+			fs.StringVar(&period, "period", stats.PeriodMonth, "month, quarter or year")
+			fs.BoolVar(&toDate, "to-date", false, "cut earlier periods at the same point as the current one")
+			fs.IntVar(&top, "top", 5, "top products listed per period (0 = none)")
+		},
+		FlagValues: map[string]func(a *app) []string{
+			"location": completeLocations,
+			"period":   func(a *app) []string { return stats.Periods },
+		},
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			c, err := stats.Compare(orders, time.Now().In(tz), period, toDate, top)
+			if err != nil {
+				return withExit(exitUsage, err)
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			return format.Write(a.stdout, output, stats.ComparisonOutput(c))
+		},
+	}
+}
+
+func statsPairsCmd() *command {
+	var (
+		allProfiles bool
+		location    string
+		item        string
+		opts        stats.PairsOptions
+	)
+	return &command{
+		Name:  "pairs",
+		Short: "Find items that are bought together",
+		Long: `Find items that are bought together.
+
+Mines pairs and triples of products (names normalized as in 'stats items')
+that appear in the same orders and lists rules such as "Bread → Butter":
+
+  support     share of orders containing all the items of the rule
+  confidence  share of orders with the left side that also have the right
+  lift        confidence divided by how common the right side is on its
+              own; above 1 the items go together more often than chance
+
+Item sets in fewer than --min-support of the orders, or fewer than two
+orders, are ignored. --item shows what goes with one product (a
+case-insensitive substring or regular expression).`,
+		Examples: []string{
+			"blinkcli stats pairs",
+			"blinkcli stats pairs --item milk",
+			"blinkcli stats pairs --size 3 --min-support 0.01",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "aggregate orders across all profiles")
+			fs.StringVar(&location, "location", "", "only count orders synced under this saved location")
+			fs.Float64Var(&opts.MinSupport, "min-support", 0.02, "minimum share of orders (0-1) for an item set")
+			fs.StringVar(&item, "item", "", "only rules with a matching item on the left side")
+			fs.IntVar(&opts.Size, "size", 0, "2 for pairs or 3 for triples only (0 = both)")
+			fs.IntVar(&opts.Top, "top", 20, "number of rules to list (0 = all)")
+		},
+		FlagValues: map[string]func(a *app) []string{
+			"location": completeLocations,
+			"item":     completeItems,
+		},
+		Run: func(a *app, args []string) error {
+			if opts.MinSupport < 0 || opts.MinSupport > 1 {
+				return usageErrorf("--min-support must be between 0 and 1")
+			}
+			if opts.Size != 0 && opts.Size != 2 && opts.Size != 3 {
+				return usageErrorf("--size must be 2, 3 or 0")
+			}
+			if item != "" {
+				opts.Item = query.ItemPattern(item)
+			}
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			rules := stats.Associations(orders, opts)
+			if len(rules) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, "No items are bought together often enough; try a lower --min-support.")
+				return nil
+			}
+			return format.Write(a.stdout, output, stats.PairsOutput(rules, a.termWidth()))
+		},
+	}
+}
+
+// loadStatsOrders loads the orders a stats command summarizes.
+func loadStatsOrders(allProfiles bool, location string) ([]blink.Order, error) {
+	orders, err := loadOrders(allProfiles)
+	if err != nil {
+		return nil, err
+	}
+	if location != "" {
+		orders = stats.FilterLocation(orders, location)
+	}
+	return orders, nil
+}
+
+func statsHeatmapCmd() *command {
+	var (
+		allProfiles bool
+		location    string
+		ascii       bool
+	)
+	return &command{
+		Name:  "heatmap",
+		Short: "Show when orders are placed by weekday and hour",
+		Long: `Show when orders are placed by weekday and hour.
+
+Draws a weekday by hour heatmap shaded by order count, followed by weekday
+totals, the busiest hour and the share of late-night orders (22:00-04:00).
+Unicode block shading is used on UTF-8 terminals, ASCII otherwise or with
+--ascii. Hours are in the timezone setting.`,
+		Examples: []string{
+			"blinkcli stats heatmap",
+			"blinkcli stats heatmap --ascii",
+			"blinkcli stats heatmap --output csv",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "aggregate orders across all profiles")
+			fs.StringVar(&location, "location", "", "only count orders synced under this saved location")
+			fs.BoolVar(&ascii, "ascii", false, "shade with ASCII characters")
+		},
+		FlagValues: map[string]func(a *app) []string{"location": completeLocations},
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			shades := stats.UnicodeShades
+			if ascii || !a.unicode() {
+				shades = stats.ASCIIShades
+			}
+			return format.Write(a.stdout, output, stats.HeatmapOutput(stats.BuildHeatmap(orders), shades))
+		},
+	}
+}
+
+func statsForecastCmd() *command {
+	var (
+		allProfiles bool
+		location    string
+	)
+	return &command{
+		Name:  "forecast",
+		Short: "Project month-end and year-end spend",
+		Long: `Project month-end and year-end spend.
+
+The forecast is the spend so far plus a baseline, the average of the last
+three complete months, scaled by each month's seasonality for the rest of
+the month and each remaining month of the year. Seasonality is a calendar
+month's average spend against the average month, once there are 12 complete
+months of history. The inputs are printed with the forecast, along with the
+plain pace for comparison.
+
+The forecast depends on today's date in the timezone setting, so unlike
+'blinkcli stats' its output changes from day to day.`,
+		Examples: []string{
+			"blinkcli stats forecast",
+			"blinkcli stats forecast --output json",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&allProfiles, "all-profiles", false, "aggregate orders across all profiles")
+			fs.StringVar(&location, "location", "", "only count orders synced under this saved location")
+		},
+		FlagValues: map[string]func(a *app) []string{"location": completeLocations},
+		Run: func(a *app, args []string) error {
+			output, err := a.outputFormat()
+			if err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			orders, err := loadStatsOrders(allProfiles, location)
+			if err != nil {
+				return err
+			}
+			if len(orders) == 0 && output == format.Table {
+				fmt.Fprintln(a.stdout, noOrdersMsg)
+				return nil
+			}
+			return format.Write(a.stdout, output, stats.ForecastOutput(stats.BuildForecast(orders, time.Now().In(tz))))
+		},
+	}
+}
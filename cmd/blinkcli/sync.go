@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+	"blinkcli/internal/store"
+)
+
+var errNotLoggedIn = errors.New("not logged in")
+
+type syncOptions struct {
+	pages    int
+	pageSize int
+	sleep    time.Duration
+	location string
+	timeZone *time.Location
+	// failOnBudget turns an exceeded budget into errBudgetExceeded.
+	failOnBudget bool
+}
+
+func syncCmd() *command {
+	var (
+		pages        int
+		pageSize     int
+		sleepMs      int
+		allProfiles  bool
+		location     string
+		failOnBudget bool
+	)
+	return &command{
+		Name:  "sync",
+		Short: "Fetch order history into the local store",
+		Long: `Fetch order history into the local store.
+
+Defaults for --pages, --page-size and --sleep-ms come from the sync.* settings
+(see 'blinkcli config list').
+
+After syncing, a warning is printed for every budget limit exceeded (see
+'blinkcli budget'). With --fail-on-budget the exit status is then 5.`,
+		Examples: []string{
+			"blinkcli sync",
+			"blinkcli sync --pages 5 --sleep-ms 500",
+			"blinkcli sync --all-profiles",
+			"blinkcli sync --fail-on-budget || notify-send 'Over budget'",
+		},
+		Flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&pages, "pages", 0, "max pages to fetch (default: setting sync.pages)")
+			fs.IntVar(&pageSize, "page-size", 0, "page size if supported by the API (default: setting sync.page_size)")
+			fs.IntVar(&sleepMs, "sleep-ms", 0, "sleep between pages in ms (default: setting sync.sleep_ms)")
+			fs.BoolVar(&allProfiles, "all-profiles", false, "sync every profile that is logged in")
+			fs.StringVar(&location, "location", "", "saved location to sync from (default: the profile's default location)")
+			fs.BoolVar(&failOnBudget, "fail-on-budget", false, "exit with status 5 when a budget is exceeded")
+		},
+		FlagValues: map[string]func(a *app) []string{"location": completeLocations},
+		Run: func(a *app, args []string) error {
+			var err error
+			if pages, err = a.settingInt("pages", "sync.pages", pages); err != nil {
+				return err
+			}
+			if pageSize, err = a.settingInt("page-size", "sync.page_size", pageSize); err != nil {
+				return err
+			}
+			if sleepMs, err = a.settingInt("sleep-ms", "sync.sleep_ms", sleepMs); err != nil {
+				return err
+			}
+			tz, err := config.TimeZone()
+			if err != nil {
+				return err
+			}
+			opts := syncOptions{
+				pages:        max(pages, 1),
+				pageSize:     pageSize,
+				sleep:        time.Duration(sleepMs) * time.Millisecond,
+				location:     location,
+				timeZone:     tz,
+				failOnBudget: failOnBudget,
+			}
+
+			if !allProfiles {
+				err := syncProfile(a, config.ActiveProfile(), opts)
+				switch {
+				case errors.Is(err, errNotLoggedIn):
+					return withExit(exitAuth, err)
+				case errors.Is(err, errBudgetExceeded):
+					return silentExit(exitBudget)
+				}
+				return err
+			}
+
+			profiles, err := config.ListProfiles()
+			if err != nil {
+				return err
+			}
+			failed, overBudget := 0, false
+			for _, profile := range profiles {
+				fmt.Fprintf(a.stdout, "== %s ==\n", profile)
+				err := syncProfile(a, profile, opts)
+				if errors.Is(err, errNotLoggedIn) {
+					fmt.Fprintln(a.stdout, "Skipped (not logged in).")
+					continue
+				}
+				if errors.Is(err, errBudgetExceeded) {
+					overBudget = true
+					continue
+				}
+				if err != nil {
+					fmt.Fprintf(a.stderr, "Error: %s: %v\n", profile, err)
+					failed++
+				}
+			}
+			if failed > 0 {
+				return silentExit(exitError)
+			}
+			if overBudget {
+				return silentExit(exitBudget)
+			}
+			return nil
+		},
+	}
+}
+
+func syncProfile(a *app, profile string, opts syncOptions) error {
+	cfg, err := config.LoadProfile(profile)
+	if err != nil {
+		return err
+	}
+	if cfg.Session == nil || cfg.Session.AccessToken == "" {
+		return fmt.Errorf("%w; run 'blinkcli --profile %s auth login'", errNotLoggedIn, profile)
+	}
+	locationName, err := cfg.ApplyLocation(opts.location)
+	if err != nil {
+		return err
+	}
+
+	st, err := store.ForProfile(profile)
+	if err != nil {
+		return err
+	}
+	existing, err := st.Load()
+	if err != nil {
+		return err
+	}
+	a.debugf("profile %s: store %s, %d orders, location %q", profile, st.Path, len(existing), locationName)
+
+	client := blink.NewClient(cfg.Session)
+	client.Location = opts.timeZone
+	ctx := context.Background()
+
+	merged := existing
+	syncedAt := time.Now().UTC().Truncate(time.Second)
+	for page := 1; page <= opts.pages; page++ {
+		started := time.Now()
+		orders, err := client.OrderHistory(ctx, page, opts.pageSize)
+		if err != nil {
+			if blink.IsUnauthorized(err) {
+				return withExit(exitAuth, fmt.Errorf("%w (try 'blinkcli auth refresh' or 'blinkcli auth login')", err))
+			}
+			return err
+		}
+		a.debugf("page %d fetched in %s", page, time.Since(started).Round(time.Millisecond))
+		for i := range orders {
+			// Order history doesn't include the delivery address, so orders
+			// are tagged with the location synced under; MergeOrders keeps
+			// the tag from the first sync.
+			orders[i].Location = locationName
+			orders[i].FirstSyncedAt = syncedAt
+			orders[i].LastSyncedAt = syncedAt
+			orders[i].SyncCount = 1
+		}
+		updated, newCount := store.MergeOrders(merged, orders)
+		fmt.Fprintf(a.stdout, "Page %d/%d: fetched %d orders, new %d\n", page, opts.pages, len(orders), newCount)
+		merged = updated
+
+		if len(orders) == 0 {
+			break
+		}
+		if page < opts.pages {
+			time.Sleep(opts.sleep)
+		}
+	}
+
+	if err := st.Save(merged); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "Sync complete. Stored %d orders.\n", len(merged))
+
+	exceeded, err := warnBudget(a, profile, cfg, merged)
+	if err != nil {
+		return err
+	}
+	if exceeded && opts.failOnBudget {
+		return errBudgetExceeded
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"text/template"
+
+	"blinkcli/internal/format"
+)
+
+// templateFlags holds --template and --template-file for report commands.
+type templateFlags struct {
+	text string
+	file string
+}
+
+func (t *templateFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&t.text, "template", "", "Go text/template to render instead of --output")
+	fs.StringVar(&t.file, "template-file", "", "file holding a Go text/template")
+}
+
+// parse returns the chosen template, or nil when neither flag was given.
+func (t *templateFlags) parse(a *app) (*template.Template, error) {
+	if t.text == "" && t.file == "" {
+		return nil, nil
+	}
+	if t.text != "" && t.file != "" {
+		return nil, usageErrorf("use either --template or --template-file")
+	}
+	if a.output != "" {
+		return nil, usageErrorf("--output cannot be combined with a template")
+	}
+	name, text := "template", t.text
+	if t.file != "" {
+		data, err := os.ReadFile(t.file)
+		if err != nil {
+			return nil, err
+		}
+		name, text = t.file, string(data)
+	}
+	tmpl, err := format.ParseTemplate(name, text)
+	if err != nil {
+		return nil, withExit(exitUsage, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package main
+
+import "os"
+
+// ttyWidth is not implemented here; COLUMNS still applies.
+func ttyWidth(f *os.File) int { return 0 }
@@ -25,6 +25,7 @@ const (
 	blinkitOrigin    = "https://blinkit.com"
 	loginPollDelay   = 2 * time.Second
 	loginTimeout     = 8 * time.Minute
+	refreshTimeout   = 45 * time.Second
 	defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
 )
 
@@ -51,26 +52,78 @@ type locationPayload struct {
 	} `json:"coords"`
 }
 
+// LoginOptions controls how the browser used for login is launched.
+type LoginOptions struct {
+	// ProfileDir is a persistent Chrome user data directory. When empty a
+	// throwaway profile is created and removed after login.
+	ProfileDir string
+	// Headless hides the browser window. Only useful with a ProfileDir that
+	// already holds a logged-in Blinkit session.
+	Headless bool
+	// Timeout bounds how long to wait for a session to appear.
+	Timeout time.Duration
+	// RemoteURL attaches to an already running Chrome started with
+	// --remote-debugging-port (ws://... or http://host:port) instead of
+	// launching one. ProfileDir, Headless and ChromePath are ignored.
+	RemoteURL string
+	// ChromePath overrides the Chrome/Chromium executable.
+	ChromePath string
+}
+
 // Login opens a visible browser window and waits for the user to sign in.
-func Login(ctx context.Context) (*config.Session, error) {
-	tmpDir, err := os.MkdirTemp("", "blinkcli-chrome-")
+func Login(ctx context.Context, opts LoginOptions) (*config.Session, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = loginTimeout
+	}
+	browserCtx, cancel, err := newBrowser(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-	// Best-effort cleanup; we keep the profile for the session duration only.
-	defer os.RemoveAll(tmpDir)
+	defer cancel()
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", false),
-		chromedp.Flag("disable-gpu", false),
-		chromedp.UserDataDir(filepath.Clean(tmpDir)),
-	)
+	if err := chromedp.Run(browserCtx,
+		network.Enable(),
+		chromedp.Navigate(blinkitURL),
+	); err != nil {
+		return nil, err
+	}
 
-	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancelAlloc()
+	if opts.RemoteURL != "" {
+		fmt.Println("Opened a Blinkit tab in the running browser. Log in there if you haven't already.")
+	} else {
+		fmt.Println("A browser window is open. Please log in to Blinkit and select an address.")
+	}
+	fmt.Println("Waiting for login to complete...")
 
-	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
-	defer cancelBrowser()
+	session, err := waitForSession(browserCtx, opts.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Refresh re-reads the session from a persistent browser profile without
+// showing a window. The profile must have been logged in before, e.g. with
+// Login and the same ProfileDir. With RemoteURL set it reads the session from
+// the running browser instead.
+func Refresh(ctx context.Context, opts LoginOptions) (*config.Session, error) {
+	if opts.ProfileDir == "" && opts.RemoteURL == "" {
+		return nil, errors.New("refresh requires a browser profile directory or remote debugging URL")
+	}
+	if opts.RemoteURL == "" {
+		if _, err := os.Stat(opts.ProfileDir); err != nil {
+			return nil, fmt.Errorf("browser profile %s: %w", opts.ProfileDir, err)
+		}
+	}
+	opts.Headless = true
+	if opts.Timeout <= 0 {
+		opts.Timeout = refreshTimeout
+	}
+	browserCtx, cancel, err := newBrowser(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
 
 	if err := chromedp.Run(browserCtx,
 		network.Enable(),
@@ -79,16 +132,70 @@ func Login(ctx context.Context) (*config.Session, error) {
 		return nil, err
 	}
 
-	fmt.Println("A browser window is open. Please log in to Blinkit and select an address.")
-	fmt.Println("Waiting for login to complete...")
+	session, err := waitForSession(browserCtx, opts.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("no Blinkit session in browser profile (log in again with 'blinkcli auth login --profile-dir'): %w", err)
+	}
+	return session, nil
+}
+
+// newBrowser starts Chrome according to opts, or opens a new tab in a remote
+// one. The returned cancel func closes the browser (or just the tab) and
+// removes any throwaway profile.
+func newBrowser(ctx context.Context, opts LoginOptions) (context.Context, context.CancelFunc, error) {
+	if opts.RemoteURL != "" {
+		allocCtx, cancelAlloc := chromedp.NewRemoteAllocator(ctx, opts.RemoteURL)
+		browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+		return browserCtx, func() {
+			cancelBrowser()
+			cancelAlloc()
+		}, nil
+	}
+
+	userDataDir := opts.ProfileDir
+	cleanup := func() {}
+	if userDataDir == "" {
+		tmpDir, err := os.MkdirTemp("", "blinkcli-chrome-")
+		if err != nil {
+			return nil, nil, err
+		}
+		userDataDir = tmpDir
+		// Best-effort cleanup; we keep the profile for the session duration only.
+		cleanup = func() { os.RemoveAll(tmpDir) }
+	} else if err := os.MkdirAll(userDataDir, 0o700); err != nil {
+		return nil, nil, err
+	}
+
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", opts.Headless),
+		chromedp.Flag("disable-gpu", opts.Headless),
+		chromedp.UserDataDir(filepath.Clean(userDataDir)),
+	)
+	if opts.ChromePath != "" {
+		allocOpts = append(allocOpts, chromedp.ExecPath(opts.ChromePath))
+	}
+	if opts.Headless {
+		// Headless Chrome advertises itself in the UA; keep the regular one.
+		allocOpts = append(allocOpts, chromedp.UserAgent(defaultUserAgent))
+	}
 
-	deadline := time.Now().Add(loginTimeout)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocOpts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	return browserCtx, func() {
+		cancelBrowser()
+		cancelAlloc()
+		cleanup()
+	}, nil
+}
+
+func waitForSession(ctx context.Context, timeout time.Duration) (*config.Session, error) {
+	deadline := time.Now().Add(timeout)
 	for {
 		if time.Now().After(deadline) {
 			return nil, errors.New("login timed out")
 		}
 
-		session, ok, err := tryReadSession(browserCtx)
+		session, ok, err := tryReadSession(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -210,24 +317,17 @@ func tryReadSession(ctx context.Context) (*config.Session, bool, error) {
 			}
 		}
 	}
-	if cookieRaw != "" {
-		for _, part := range strings.Split(cookieRaw, ";") {
-			part = strings.TrimSpace(part)
-			if part == "" {
-				continue
-			}
-			name, value, ok := strings.Cut(part, "=")
-			if !ok {
-				continue
-			}
-			name = strings.TrimSpace(name)
-			if name == "" {
-				continue
-			}
-			session.Cookies[name] = strings.TrimSpace(value)
-		}
-	}
+	parseCookieHeader(cookieRaw, session.Cookies)
 
+	completeSession(session, true)
+
+	return session, true, nil
+}
+
+// completeSession fills fields that can be derived from others: coordinates
+// from cookies, cookies from fields, and version defaults. When fetch is true
+// a missing auth_key is requested from Blinkit using the session cookies.
+func completeSession(session *config.Session, fetch bool) {
 	if session.Lat == 0 || session.Lon == 0 {
 		if lat, ok := session.Cookies["gr_1_lat"]; ok {
 			if val, err := strconv.ParseFloat(lat, 64); err == nil {
@@ -243,11 +343,7 @@ func tryReadSession(ctx context.Context) (*config.Session, bool, error) {
 
 	config.PopulateDerivedCookies(session)
 
-	if session.AccessToken == "" || session.AuthKey == "" || session.DeviceID == "" || session.SessionID == "" {
-		// Still treat as logged in if access token exists, but caller might need to refresh.
-	}
-
-	if session.AuthKey == "" && len(session.Cookies) > 0 {
+	if fetch && session.AuthKey == "" && len(session.Cookies) > 0 {
 		if key, err := fetchAuthKey(session); err == nil {
 			session.AuthKey = key
 		}
@@ -262,8 +358,6 @@ func tryReadSession(ctx context.Context) (*config.Session, bool, error) {
 	if session.AppVersion == "" {
 		session.AppVersion = session.WebAppVersion
 	}
-
-	return session, true, nil
 }
 
 // Status returns a human-readable status line and whether a session exists.
@@ -323,6 +417,26 @@ func fetchAuthKey(session *config.Session) (string, error) {
 	return payload.AuthKey, nil
 }
 
+// parseCookieHeader adds the name=value pairs of a Cookie header (or
+// document.cookie string) to cookies.
+func parseCookieHeader(raw string, cookies map[string]string) {
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		cookies[name] = strings.TrimSpace(value)
+	}
+}
+
 func cookieHeader(cookies map[string]string) string {
 	parts := make([]string, 0, len(cookies))
 	for k, v := range cookies {
@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/config"
+)
+
+// requiredImportFields are the session fields an import cannot work without;
+// the rest are reported but can be recovered (auth_key) or are optional.
+var requiredImportFields = []string{"access_token", "device_id"}
+
+// MissingFieldsError reports session fields an import could not find.
+type MissingFieldsError struct {
+	Missing []string
+}
+
+func (e *MissingFieldsError) Error() string {
+	return fmt.Sprintf("session is missing required fields: %s", strings.Join(e.Missing, ", "))
+}
+
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				URL     string      `json:"url"`
+				Headers []harHeader `json:"headers"`
+				Cookies []harHeader `json:"cookies"`
+			} `json:"request"`
+			Response struct {
+				Cookies []harHeader `json:"cookies"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type exportedCookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain"`
+}
+
+// ImportHAR builds a session from a HAR capture of a logged-in blinkit.com tab.
+// Later requests win, so a capture that spans a re-login yields the newest values.
+func ImportHAR(r io.Reader) (*config.Session, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("parse HAR: %w", err)
+	}
+
+	session := &config.Session{Cookies: map[string]string{}}
+	matched := 0
+	for _, entry := range har.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || !isBlinkitHost(u.Hostname()) {
+			continue
+		}
+		matched++
+		for _, h := range entry.Request.Headers {
+			applyHeader(session, h.Name, h.Value)
+		}
+		for _, c := range entry.Request.Cookies {
+			session.Cookies[c.Name] = c.Value
+		}
+		for _, c := range entry.Response.Cookies {
+			session.Cookies[c.Name] = c.Value
+		}
+	}
+	if matched == 0 {
+		return nil, errors.New("HAR contains no blinkit.com requests")
+	}
+	applyCookieFields(session)
+	return session, nil
+}
+
+// ImportNetscapeCookies builds a session from a Netscape cookies.txt export.
+func ImportNetscapeCookies(r io.Reader) (*config.Session, error) {
+	session := &config.Session{Cookies: map[string]string{}}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// curl and most exporters mark HttpOnly cookies with this prefix.
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			continue
+		}
+		if !isBlinkitHost(fields[0]) {
+			continue
+		}
+		session.Cookies[fields[5]] = fields[6]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(session.Cookies) == 0 {
+		return nil, errors.New("cookie file contains no blinkit.com cookies")
+	}
+	applyCookieFields(session)
+	return session, nil
+}
+
+// ImportCookieJSON builds a session from a JSON cookie export, either a bare
+// array of {name, value, domain} objects or an object with a "cookies" array.
+func ImportCookieJSON(r io.Reader) (*config.Session, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var cookies []exportedCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		var wrapped struct {
+			Cookies []exportedCookie `json:"cookies"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("parse cookie JSON: %w", err)
+		}
+		cookies = wrapped.Cookies
+	}
+
+	session := &config.Session{Cookies: map[string]string{}}
+	for _, c := range cookies {
+		if c.Domain != "" && !isBlinkitHost(c.Domain) {
+			continue
+		}
+		session.Cookies[c.Name] = c.Value
+	}
+	if len(session.Cookies) == 0 {
+		return nil, errors.New("cookie JSON contains no blinkit.com cookies")
+	}
+	applyCookieFields(session)
+	return session, nil
+}
+
+// FinishImport derives the remaining fields of an imported session, fetching
+// auth_key when fetch is true, and validates it. It returns the non-required
+// fields that are still missing.
+func FinishImport(session *config.Session, fetch bool) ([]string, error) {
+	completeSession(session, fetch)
+	session.UpdatedAt = time.Now()
+
+	missing := session.MissingFields()
+	var required []string
+	for _, field := range missing {
+		for _, req := range requiredImportFields {
+			if field == req {
+				required = append(required, field)
+			}
+		}
+	}
+	if len(required) > 0 {
+		return missing, &MissingFieldsError{Missing: required}
+	}
+	return missing, nil
+}
+
+func applyHeader(session *config.Session, name, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	switch strings.ToLower(name) {
+	case "access_token":
+		session.AccessToken = value
+	case "auth_key":
+		session.AuthKey = value
+	case "device_id":
+		session.DeviceID = value
+	case "session_uuid":
+		session.SessionID = value
+	case "lat":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			session.Lat = v
+		}
+	case "lon":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			session.Lon = v
+		}
+	case "web_app_version":
+		session.WebAppVersion = value
+	case "app_version":
+		session.AppVersion = value
+	case "rn_bundle_version":
+		session.RNBundleVersion = value
+	case "user-agent":
+		session.UserAgent = value
+	case "cookie":
+		parseCookieHeader(value, session.Cookies)
+	}
+}
+
+// applyCookieFields fills session fields that Blinkit mirrors into gr_1_*
+// cookies, without overriding values already taken from request headers.
+func applyCookieFields(session *config.Session) {
+	cookie := func(name string) string {
+		v := session.Cookies[name]
+		if unescaped, err := url.QueryUnescape(v); err == nil {
+			return unescaped
+		}
+		return v
+	}
+	if session.AccessToken == "" {
+		session.AccessToken = cookie("gr_1_accessToken")
+	}
+	if session.DeviceID == "" {
+		session.DeviceID = cookie("gr_1_deviceId")
+	}
+	if session.Locality == "" {
+		session.Locality = cookie("gr_1_locality")
+	}
+	if session.Landmark == "" {
+		session.Landmark = cookie("gr_1_landmark")
+	}
+}
+
+func isBlinkitHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), ".")
+	return host == "blinkit.com" || strings.HasSuffix(host, ".blinkit.com")
+}
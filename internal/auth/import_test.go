@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestImportHAR(t *testing.T) {
+	har := `{
+		"log": {
+			"entries": [
+				{
+					"request": {
+						"url": "https://example.com/",
+						"headers": [{"name": "access_token", "value": "wrong"}]
+					}
+				},
+				{
+					"request": {
+						"url": "https://blinkit.com/v1/layout/order_history",
+						"headers": [
+							{"name": "access_token", "value": "tok"},
+							{"name": "auth_key", "value": "key"},
+							{"name": "device_id", "value": "dev"},
+							{"name": "session_uuid", "value": "sess"},
+							{"name": "lat", "value": "28.5"},
+							{"name": "lon", "value": "77.2"},
+							{"name": "Cookie", "value": "gr_1_locality=Saket; __cf_bm=cf"}
+						]
+					}
+				}
+			]
+		}
+	}`
+
+	session, err := ImportHAR(strings.NewReader(har))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.AccessToken != "tok" || session.AuthKey != "key" || session.DeviceID != "dev" || session.SessionID != "sess" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+	if session.Lat != 28.5 || session.Lon != 77.2 || session.Locality != "Saket" {
+		t.Fatalf("unexpected location: %+v", session)
+	}
+	if session.Cookies["__cf_bm"] != "cf" {
+		t.Fatalf("expected __cf_bm cookie, got %v", session.Cookies)
+	}
+	missing, err := FinishImport(session, false)
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("expected complete session, got missing=%v err=%v", missing, err)
+	}
+}
+
+func TestImportNetscapeCookies(t *testing.T) {
+	txt := "# Netscape HTTP Cookie File\n" +
+		".blinkit.com\tTRUE\t/\tTRUE\t0\tgr_1_accessToken\tv2%3A%3Atok\n" +
+		"#HttpOnly_.blinkit.com\tTRUE\t/\tTRUE\t0\tgr_1_deviceId\tdev\n" +
+		"blinkit.com\tFALSE\t/\tFALSE\t0\tgr_1_lat\t28.5\n" +
+		".example.com\tTRUE\t/\tFALSE\t0\tgr_1_lon\t1\n"
+
+	session, err := ImportNetscapeCookies(strings.NewReader(txt))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.AccessToken != "v2::tok" || session.DeviceID != "dev" {
+		t.Fatalf("unexpected session: %+v", session)
+	}
+	missing, err := FinishImport(session, false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if session.Lat != 28.5 {
+		t.Fatalf("expected lat from cookie, got %v", session.Lat)
+	}
+	want := []string{"auth_key", "session_uuid", "lon"}
+	if strings.Join(missing, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected missing %v, got %v", want, missing)
+	}
+}
+
+func TestImportCookieJSONMissingRequired(t *testing.T) {
+	session, err := ImportCookieJSON(strings.NewReader(`{"cookies": [{"name": "gr_1_lat", "value": "1", "domain": "blinkit.com"}]}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	_, err = FinishImport(session, false)
+	var missingErr *MissingFieldsError
+	if !errors.As(err, &missingErr) {
+		t.Fatalf("expected MissingFieldsError, got %v", err)
+	}
+	if strings.Join(missingErr.Missing, ",") != "access_token,device_id" {
+		t.Fatalf("unexpected required fields: %v", missingErr.Missing)
+	}
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+)
+
+// SessionState is the outcome of checking a session against Blinkit.
+type SessionState string
+
+const (
+	StateValid   SessionState = "valid"
+	StateExpired SessionState = "expired"
+	StateUnknown SessionState = "unknown"
+)
+
+// CheckReport describes a session validated with a live request.
+type CheckReport struct {
+	State SessionState
+	// Detail explains an expired or unknown state.
+	Detail string
+	// Missing lists empty session fields and absent key cookies.
+	Missing []string
+	// TokenExpiry is zero when the access token carries no expiry.
+	TokenExpiry time.Time
+	Counts      blink.OrderCount
+}
+
+// Check makes a lightweight authenticated call (order_count) to find out
+// whether the stored session is still accepted.
+func Check(ctx context.Context, session *config.Session, now time.Time) CheckReport {
+	report := CheckReport{Missing: session.MissingFields()}
+	if session == nil || session.AccessToken == "" {
+		report.State = StateExpired
+		report.Detail = "no access token stored"
+		return report
+	}
+	if !hasCookie(session.Cookies, "__cf_bm") {
+		report.Missing = append(report.Missing, "__cf_bm")
+	}
+	if exp, ok := TokenExpiry(session.AccessToken); ok {
+		report.TokenExpiry = exp
+	}
+
+	counts, err := blink.NewClient(session).OrderCount(ctx)
+	switch {
+	case err == nil:
+		report.State = StateValid
+		report.Counts = counts
+	case blink.IsUnauthorized(err):
+		report.State = StateExpired
+		report.Detail = err.Error()
+	default:
+		report.State = StateUnknown
+		report.Detail = err.Error()
+	}
+	if report.State != StateExpired && !report.TokenExpiry.IsZero() && now.After(report.TokenExpiry) {
+		report.State = StateExpired
+		report.Detail = "access token expired"
+	}
+	return report
+}
+
+// FormatCheck renders a CheckReport for the terminal.
+func FormatCheck(report CheckReport) string {
+	lines := []string{fmt.Sprintf("Session: %s", report.State)}
+	if report.Detail != "" {
+		lines = append(lines, fmt.Sprintf("Detail: %s", report.Detail))
+	}
+	if report.State == StateValid {
+		lines = append(lines, fmt.Sprintf("Orders: %d delivered, %d live, %d cancelled",
+			report.Counts.Delivered, report.Counts.Live, report.Counts.Cancelled))
+	}
+	if report.TokenExpiry.IsZero() {
+		lines = append(lines, "Token expiry: unknown")
+	} else {
+		lines = append(lines, fmt.Sprintf("Token expiry: %s", report.TokenExpiry.Format(time.RFC3339)))
+	}
+	if len(report.Missing) > 0 {
+		lines = append(lines, fmt.Sprintf("Missing: %s", strings.Join(report.Missing, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TokenExpiry decodes the exp claim when the access token is a JWT.
+func TokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == "" {
+		return time.Time{}, false
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil || exp <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
+
+func hasCookie(cookies map[string]string, name string) bool {
+	_, ok := cookies[name]
+	return ok
+}
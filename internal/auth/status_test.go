@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","exp":1767225600}`))
+	exp, ok := TokenExpiry("eyJhbGciOiJIUzI1NiJ9." + payload + ".sig")
+	if !ok {
+		t.Fatalf("expected expiry to decode")
+	}
+	if !exp.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected expiry: %v", exp)
+	}
+
+	if _, ok := TokenExpiry("v2::0a1b2c3d"); ok {
+		t.Fatalf("expected opaque token to have no expiry")
+	}
+}
@@ -23,16 +23,39 @@ const (
 	defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
 )
 
+// StatusError is returned when an endpoint answers with a non-2xx status.
+type StatusError struct {
+	Endpoint   string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s request failed: %s", e.Endpoint, e.Status)
+}
+
+// IsUnauthorized reports whether err is a rejection of the session itself.
+func IsUnauthorized(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	return statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden
+}
+
 // Client calls Blinkit web endpoints using a captured session.
 type Client struct {
 	HTTP    *http.Client
 	Session *config.Session
+	// Location is the time zone order dates are interpreted in.
+	Location *time.Location
 }
 
 func NewClient(session *config.Session) *Client {
 	return &Client{
-		HTTP:    &http.Client{Timeout: 20 * time.Second},
-		Session: session,
+		HTTP:     &http.Client{Timeout: 20 * time.Second},
+		Session:  session,
+		Location: time.Local,
 	}
 }
 
@@ -55,7 +78,7 @@ func (c *Client) OrderCount(ctx context.Context) (OrderCount, error) {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return OrderCount{}, fmt.Errorf("order_count request failed: %s", resp.Status)
+		return OrderCount{}, &StatusError{Endpoint: "order_count", StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -101,13 +124,13 @@ func (c *Client) OrderHistory(ctx context.Context, page, pageSize int) ([]Order,
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("order_history request failed: %s", resp.Status)
+		return nil, &StatusError{Endpoint: "order_history", StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	return ParseOrderHistory(respBody, time.Now())
+	return ParseOrderHistory(respBody, time.Now().In(c.Location))
 }
 
 func applyHeaders(req *http.Request, session *config.Session) {
@@ -69,6 +69,25 @@ type Order struct {
 	Date         time.Time `json:"date"`
 	RawDate      string    `json:"raw_date,omitempty"`
 	Items        []string  `json:"items,omitempty"`
+	// Location is the saved location active during the first sync that saw
+	// the order. Order history covers the whole account and doesn't say
+	// where an order was delivered, so this is not its delivery address.
+	Location string `json:"location,omitempty"`
+	// Deeplink opens the order in the Blinkit app.
+	Deeplink string `json:"deeplink,omitempty"`
+	// FirstSyncedAt, LastSyncedAt and SyncCount record the syncs that saw
+	// the order.
+	FirstSyncedAt time.Time `json:"first_synced_at,omitzero"`
+	LastSyncedAt  time.Time `json:"last_synced_at,omitzero"`
+	SyncCount     int       `json:"sync_count,omitempty"`
+}
+
+// OrderWebURL returns the blinkit.com page of an order.
+func OrderWebURL(orderID string) string {
+	if orderID == "" {
+		return ""
+	}
+	return "https://blinkit.com/account/orders/" + url.PathEscape(orderID)
 }
 
 // OrderCount captures the /v1/order_count response.
@@ -111,6 +130,7 @@ func parseOrderContainer(sn snippet, now time.Time) (Order, bool) {
 	if sn.Tracking != nil {
 		order.ID = sn.Tracking.CommonAttributes.OrderID
 		order.Status = sn.Tracking.CommonAttributes.OrderStatus
+		order.Deeplink = sn.Tracking.CommonAttributes.Deeplink
 		if order.CartID == "" {
 			order.ID, order.CartID = parseDeeplink(sn.Tracking.CommonAttributes.Deeplink, order.ID)
 		}
@@ -0,0 +1,193 @@
+// Package budget compares spend in the current week and month with the
+// limits configured for a profile.
+package budget
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+	"blinkcli/internal/format"
+)
+
+// Categorizer names the category of an item, or "" when it has none.
+type Categorizer func(item string) string
+
+// Status is the state of one limit in the current period.
+type Status struct {
+	// Name is "monthly", "weekly" or "category:<name>".
+	Name   string    `json:"name"`
+	Period string    `json:"period"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Limit  int       `json:"limit_rupees"`
+	Spent  int       `json:"spent_rupees"`
+	// Projected extrapolates Spent at the current pace to the period end.
+	Projected int  `json:"projected_rupees"`
+	Exceeded  bool `json:"exceeded"`
+}
+
+// Remaining is the amount left before the limit, negative when over.
+func (s Status) Remaining() int {
+	return s.Limit - s.Spent
+}
+
+// Evaluate measures every limit of b for the periods containing now. Weeks
+// start on Monday. Cancelled orders don't count. Category spend splits each
+// order's amount evenly across its items, categorized by categorize.
+func Evaluate(b *config.Budget, orders []blink.Order, now time.Time, categorize Categorizer) []Status {
+	if b.IsZero() {
+		return nil
+	}
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	weekStart := time.Date(now.Year(), now.Month(), now.Day()-(int(now.Weekday())+6)%7, 0, 0, 0, 0, now.Location())
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	var statuses []Status
+	if b.Monthly > 0 {
+		statuses = append(statuses, measure("monthly", monthStart.Format("2006-01"), monthStart, monthEnd, now, b.Monthly,
+			orders, func(blink.Order) float64 { return 1 }))
+	}
+	if b.Weekly > 0 {
+		year, week := now.ISOWeek()
+		statuses = append(statuses, measure("weekly", fmt.Sprintf("%d-W%02d", year, week), weekStart, weekEnd, now, b.Weekly,
+			orders, func(blink.Order) float64 { return 1 }))
+	}
+
+	names := make([]string, 0, len(b.Categories))
+	for name := range b.Categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		share := func(order blink.Order) float64 {
+			if categorize == nil || len(order.Items) == 0 {
+				return 0
+			}
+			n := 0
+			for _, item := range order.Items {
+				if strings.EqualFold(categorize(item), name) {
+					n++
+				}
+			}
+			return float64(n) / float64(len(order.Items))
+		}
+		statuses = append(statuses, measure("category:"+name, monthStart.Format("2006-01"), monthStart, monthEnd, now,
+			b.Categories[name], orders, share))
+	}
+	return statuses
+}
+
+// measure sums share × amount over the orders placed in [start, end).
+func measure(name, period string, start, end, now time.Time, limit int, orders []blink.Order, share func(blink.Order) float64) Status {
+	spent := 0.0
+	for _, order := range orders {
+		if order.Date.Before(start) || !order.Date.Before(end) || cancelled(order) {
+			continue
+		}
+		spent += share(order) * float64(order.AmountRupees)
+	}
+	s := Status{Name: name, Period: period, Start: start, End: end, Limit: limit, Spent: int(math.Round(spent))}
+	elapsed := now.Sub(start).Hours()
+	total := end.Sub(start).Hours()
+	s.Projected = s.Spent
+	if elapsed > 0 && elapsed < total {
+		s.Projected = int(math.Round(spent * total / elapsed))
+	}
+	s.Exceeded = s.Spent > s.Limit
+	return s
+}
+
+func cancelled(order blink.Order) bool {
+	return strings.Contains(strings.ToLower(order.Status), "cancel")
+}
+
+// Exceeded returns the statuses over their limit.
+func Exceeded(statuses []Status) []Status {
+	var over []Status
+	for _, s := range statuses {
+		if s.Exceeded {
+			over = append(over, s)
+		}
+	}
+	return over
+}
+
+// Warning describes an exceeded limit in one line.
+func Warning(s Status) string {
+	return fmt.Sprintf("%s budget exceeded for %s: spent %s of %s", s.Name, s.Period, format.Rupees(s.Spent), format.Rupees(s.Limit))
+}
+
+// Output bundles every rendering of budget statuses.
+func Output(statuses []Status) format.Output {
+	records := make([]any, len(statuses))
+	for i, s := range statuses {
+		records[i] = s
+	}
+	return format.Output{
+		Text:    func() string { return Format(statuses) },
+		Value:   statuses,
+		Records: records,
+		Rows:    func() format.Tabular { return rows(statuses) },
+	}
+}
+
+var columns = []format.Column{
+	{Header: "BUDGET"},
+	{Header: "PERIOD"},
+	{Header: "SPENT", Right: true},
+	{Header: "LIMIT", Right: true},
+	{Header: "USED", Right: true},
+	{Header: "LEFT", Right: true},
+	{Header: "PROJECTED", Right: true},
+	{Header: ""},
+}
+
+// Format renders statuses as a table, flagging limits that are exceeded
+// or projected to be.
+func Format(statuses []Status) string {
+	table := make([][]string, 0, len(statuses))
+	for _, s := range statuses {
+		note := ""
+		switch {
+		case s.Exceeded:
+			note = "over budget"
+		case s.Projected > s.Limit:
+			note = "on track to exceed"
+		}
+		table = append(table, []string{
+			s.Name,
+			s.Period,
+			format.Rupees(s.Spent),
+			format.Rupees(s.Limit),
+			fmt.Sprintf("%.0f%%", 100*float64(s.Spent)/float64(s.Limit)),
+			format.Rupees(s.Remaining()),
+			format.Rupees(s.Projected),
+			note,
+		})
+	}
+	return format.RenderTable(columns, table, 0)
+}
+
+func rows(statuses []Status) format.Tabular {
+	t := format.Tabular{Header: []string{"name", "period", "start", "end", "limit_rupees", "spent_rupees", "projected_rupees", "exceeded"}}
+	for _, s := range statuses {
+		t.Rows = append(t.Rows, []string{
+			s.Name,
+			s.Period,
+			s.Start.Format(time.RFC3339),
+			s.End.Format(time.RFC3339),
+			strconv.Itoa(s.Limit),
+			strconv.Itoa(s.Spent),
+			strconv.Itoa(s.Projected),
+			strconv.FormatBool(s.Exceeded),
+		})
+	}
+	return t
+}
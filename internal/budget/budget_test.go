@@ -0,0 +1,58 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/config"
+)
+
+func TestEvaluate(t *testing.T) {
+	// Wednesday 10 April 2024, noon: the month is 9.5 of 30 days in.
+	now := time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)
+	orders := []blink.Order{
+		{Date: time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC), AmountRupees: 1000, Items: []string{"Amul Milk", "Bread"}},
+		{Date: time.Date(2024, 4, 8, 9, 0, 0, 0, time.UTC), AmountRupees: 900, Items: []string{"Lays Chips"}},
+		{Date: time.Date(2024, 4, 9, 9, 0, 0, 0, time.UTC), AmountRupees: 5000, Status: "Cancelled"},
+		{Date: time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), AmountRupees: 7000},
+	}
+	b := &config.Budget{Monthly: 5000, Weekly: 800, Categories: map[string]int{"milk": 300}}
+
+	categorize := func(item string) string {
+		if strings.Contains(item, "Milk") {
+			return "milk"
+		}
+		return ""
+	}
+	statuses := Evaluate(b, orders, now, categorize)
+	if len(statuses) != 3 {
+		t.Fatalf("expected monthly, weekly and one category, got %+v", statuses)
+	}
+	monthly, weekly, milk := statuses[0], statuses[1], statuses[2]
+	if monthly.Name != "monthly" || monthly.Period != "2024-04" || monthly.Spent != 1900 || monthly.Exceeded {
+		t.Fatalf("unexpected monthly status %+v", monthly)
+	}
+	if monthly.Projected != 6000 || monthly.Remaining() != 3100 {
+		t.Fatalf("expected 1900 over 9.5 days to project to 6000, got %+v", monthly)
+	}
+	if weekly.Period != "2024-W15" || !weekly.Start.Equal(time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the week to start on Monday 8 April, got %+v", weekly)
+	}
+	if weekly.Spent != 900 || !weekly.Exceeded {
+		t.Fatalf("unexpected weekly status %+v", weekly)
+	}
+	if milk.Name != "category:milk" || milk.Spent != 500 || !milk.Exceeded {
+		t.Fatalf("expected half of the first order on milk, got %+v", milk)
+	}
+	if over := Exceeded(statuses); len(over) != 2 {
+		t.Fatalf("expected two exceeded limits, got %+v", over)
+	}
+}
+
+func TestEvaluateNoBudget(t *testing.T) {
+	if statuses := Evaluate(nil, nil, time.Now(), nil); statuses != nil {
+		t.Fatalf("expected no statuses, got %+v", statuses)
+	}
+}
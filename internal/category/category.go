@@ -0,0 +1,167 @@
+// Package category assigns shopping categories to item names with keyword
+// and regular expression rules. Built-in rules are embedded from
+// default_rules.json; rules from the profile's config take precedence.
+package category
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"blinkcli/internal/config"
+)
+
+// Uncategorized labels items no rule matches in breakdowns.
+const Uncategorized = "uncategorized"
+
+// Rule sources reported by Match.
+const (
+	SourceConfig  = "config"
+	SourceDefault = "default"
+)
+
+//go:embed default_rules.json
+var defaultRulesJSON []byte
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []config.CategoryRule {
+	var rules []config.CategoryRule
+	if err := json.Unmarshal(defaultRulesJSON, &rules); err != nil {
+		panic("category: bad default_rules.json: " + err.Error())
+	}
+	return rules
+}
+
+// Match explains a categorization.
+type Match struct {
+	Category string `json:"category"`
+	// Rule is the keyword or pattern that matched.
+	Rule   string `json:"rule"`
+	Source string `json:"source"`
+}
+
+type matcher struct {
+	category, rule, source string
+	re                     *regexp.Regexp
+	// group is the submatch measured for the longest match.
+	group int
+}
+
+// Categorizer matches item names against compiled rules.
+type Categorizer struct {
+	matchers   []matcher
+	categories []string
+}
+
+// New compiles the user rules followed by the defaults.
+func New(user []config.CategoryRule) (*Categorizer, error) {
+	c := &Categorizer{}
+	if err := c.add(user, SourceConfig); err != nil {
+		return nil, err
+	}
+	if err := c.add(DefaultRules(), SourceDefault); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate checks that a rule has a category and its patterns compile.
+func Validate(rule config.CategoryRule) error {
+	_, err := compile(rule, "")
+	return err
+}
+
+func compile(rule config.CategoryRule, source string) ([]matcher, error) {
+	category := strings.ToLower(strings.TrimSpace(rule.Category))
+	if category == "" {
+		return nil, fmt.Errorf("category rule without a category")
+	}
+	var matchers []matcher
+	for _, kw := range rule.Keywords {
+		kw = strings.ToLower(strings.Join(strings.Fields(kw), " "))
+		if kw == "" {
+			continue
+		}
+		// Whole words only, allowing a plural: "egg" matches "Eggs" but
+		// not "Eggless".
+		re := regexp.MustCompile(`(?i)(?:^|[^\pL\pN])(` + regexp.QuoteMeta(kw) + `(?:s|es)?)(?:$|[^\pL\pN])`)
+		matchers = append(matchers, matcher{category, kw, source, re, 1})
+	}
+	for _, pattern := range rule.Patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("category %s: invalid pattern %q: %w", category, pattern, err)
+		}
+		matchers = append(matchers, matcher{category, pattern, source, re, 0})
+	}
+	return matchers, nil
+}
+
+func (c *Categorizer) add(rules []config.CategoryRule, source string) error {
+	for _, rule := range rules {
+		matchers, err := compile(rule, source)
+		if err != nil {
+			return err
+		}
+		c.matchers = append(c.matchers, matchers...)
+		name := strings.ToLower(strings.TrimSpace(rule.Category))
+		if !contains(c.categories, name) {
+			c.categories = append(c.categories, name)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Match finds the category of an item. Config rules win over the defaults;
+// within each, the longest match wins, so "Peanut Butter" is not dairy
+// because of "butter".
+func (c *Categorizer) Match(item string) (Match, bool) {
+	best, bestLen := -1, 0
+	for i, m := range c.matchers {
+		if best >= 0 && m.source != c.matchers[best].source {
+			break
+		}
+		loc := m.re.FindStringSubmatchIndex(item)
+		if loc == nil {
+			continue
+		}
+		if n := loc[2*m.group+1] - loc[2*m.group]; best < 0 || n > bestLen {
+			best, bestLen = i, n
+		}
+	}
+	if best < 0 {
+		return Match{}, false
+	}
+	m := c.matchers[best]
+	return Match{Category: m.category, Rule: m.rule, Source: m.source}, true
+}
+
+// Category returns the category of an item, or "" when no rule matches.
+func (c *Categorizer) Category(item string) string {
+	m, _ := c.Match(item)
+	return m.Category
+}
+
+// Categories lists every category with a rule, sorted.
+func (c *Categorizer) Categories() []string {
+	names := append([]string{}, c.categories...)
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether a category has a rule.
+func (c *Categorizer) Has(category string) bool {
+	return contains(c.categories, strings.ToLower(category))
+}
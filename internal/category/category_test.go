@@ -0,0 +1,54 @@
+package category
+
+import (
+	"testing"
+
+	"blinkcli/internal/config"
+)
+
+func TestDefaultRules(t *testing.T) {
+	c, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"Amul Taaza Toned Milk (500 ml)": "dairy",
+		"Cadbury Dairy Milk Silk":        "sweets & chocolate",
+		"Pintola Peanut Butter Crunchy":  "breakfast & spreads",
+		"Farm Fresh Eggs (Pack of 6)":    "eggs & meat",
+		"Eggless Mayonnaise":             "breakfast & spreads",
+		"Lay's Classic Salted Chips":     "snacks",
+		"Watermelon":                     "fruits & vegetables",
+		"Duracell AA Batteries":          "household",
+		"Mystery Gadget":                 "",
+	}
+	for item, want := range cases {
+		if got := c.Category(item); got != want {
+			m, _ := c.Match(item)
+			t.Errorf("%q: expected %q, got %q (%+v)", item, want, got, m)
+		}
+	}
+}
+
+func TestConfigRulesWin(t *testing.T) {
+	c, err := New([]config.CategoryRule{
+		{Category: "Protein", Keywords: []string{"milk"}},
+		{Category: "snacks", Patterns: []string{`gadget`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := c.Match("Cadbury Dairy Milk")
+	if !ok || m.Category != "protein" || m.Source != SourceConfig || m.Rule != "milk" {
+		t.Fatalf("expected the config rule to win, got %+v", m)
+	}
+	if got := c.Category("Mystery Gadget"); got != "snacks" {
+		t.Fatalf("expected the pattern to match, got %q", got)
+	}
+	if !c.Has("protein") || !c.Has("dairy") {
+		t.Fatalf("expected config and default categories, got %v", c.Categories())
+	}
+	if err := Validate(config.CategoryRule{Category: "x", Patterns: []string{"("}}); err == nil {
+		t.Fatal("expected an invalid pattern to be rejected")
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Budget holds a profile's spending limits in rupees. Zero means no limit.
+type Budget struct {
+	Monthly int `json:"monthly,omitempty"`
+	Weekly  int `json:"weekly,omitempty"`
+	// Categories are monthly limits keyed by category name.
+	Categories map[string]int `json:"categories,omitempty"`
+}
+
+// IsZero reports whether no limit is set.
+func (b *Budget) IsZero() bool {
+	return b == nil || (b.Monthly == 0 && b.Weekly == 0 && len(b.Categories) == 0)
+}
+
+// SetCategoryLimit parses "name=amount" and stores it; amount 0 removes the
+// category.
+func (b *Budget) SetCategoryLimit(spec string) error {
+	name, value, ok := strings.Cut(spec, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !ok || name == "" {
+		return fmt.Errorf("invalid category limit %q (want name=amount)", spec)
+	}
+	amount, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || amount < 0 {
+		return fmt.Errorf("invalid amount in %q", spec)
+	}
+	if amount == 0 {
+		delete(b.Categories, name)
+		return nil
+	}
+	if b.Categories == nil {
+		b.Categories = map[string]int{}
+	}
+	b.Categories[name] = amount
+	return nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import "strings"
+
+// CategoryRule assigns Category to items whose name contains one of Keywords
+// as whole words or matches one of Patterns (case-insensitive regular
+// expressions).
+type CategoryRule struct {
+	Category string   `json:"category"`
+	Keywords []string `json:"keywords,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// AddCategoryRule merges rule into the rule for the same category, adding
+// the keywords and patterns it doesn't have yet.
+func (c *Config) AddCategoryRule(rule CategoryRule) {
+	rule.Category = strings.ToLower(strings.TrimSpace(rule.Category))
+	for i := range c.CategoryRules {
+		existing := &c.CategoryRules[i]
+		if existing.Category != rule.Category {
+			continue
+		}
+		existing.Keywords = appendMissing(existing.Keywords, rule.Keywords)
+		existing.Patterns = appendMissing(existing.Patterns, rule.Patterns)
+		return
+	}
+	c.CategoryRules = append(c.CategoryRules, CategoryRule{
+		Category: rule.Category,
+		Keywords: appendMissing(nil, rule.Keywords),
+		Patterns: appendMissing(nil, rule.Patterns),
+	})
+}
+
+// RemoveCategoryRule deletes the rule for a category and reports whether it
+// existed.
+func (c *Config) RemoveCategoryRule(category string) bool {
+	for i, rule := range c.CategoryRules {
+		if strings.EqualFold(rule.Category, category) {
+			c.CategoryRules = append(c.CategoryRules[:i], c.CategoryRules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func appendMissing(list, values []string) []string {
+	for _, v := range values {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
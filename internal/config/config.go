@@ -40,10 +40,37 @@ type Session struct {
 // Config is stored on disk in the user's config directory.
 type Config struct {
 	Session *Session `json:"session,omitempty"`
+	// BrowserProfileDir is the persistent Chrome profile used by
+	// 'auth login --profile-dir', reused by 'auth refresh'.
+	BrowserProfileDir string `json:"browser_profile_dir,omitempty"`
+	// SecretBackend says where Session is kept (see SecretBackends). Empty
+	// means a legacy config with the session inline.
+	SecretBackend string `json:"secret_backend,omitempty"`
+	// CurrentProfile is only read from the default profile's config.json and
+	// selects the profile used when neither --profile nor BLINKCLI_PROFILE is set.
+	CurrentProfile string `json:"current_profile,omitempty"`
+	// Locations are the profile's saved delivery addresses.
+	Locations []Location `json:"locations,omitempty"`
+	// DefaultLocation is used when a command gets no --location.
+	DefaultLocation string `json:"default_location,omitempty"`
+	// Settings holds user defaults (see Settings); only read from the
+	// default profile's config.json.
+	Settings map[string]string `json:"settings,omitempty"`
+	// Budget caps the profile's spend.
+	Budget *Budget `json:"budget,omitempty"`
+	// CategoryRules are checked before the built-in category rules.
+	CategoryRules []CategoryRule `json:"category_rules,omitempty"`
 }
 
-// ConfigDir returns the OS-specific config directory for blinkcli.
+// ConfigDirEnv relocates the config directory.
+const ConfigDirEnv = "BLINKCLI_CONFIG_DIR"
+
+// ConfigDir returns the OS-specific config directory for blinkcli, or
+// BLINKCLI_CONFIG_DIR when set.
 func ConfigDir() (string, error) {
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		return expandHome(dir)
+	}
 	base, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
@@ -51,30 +78,148 @@ func ConfigDir() (string, error) {
 	return filepath.Join(base, appDirName), nil
 }
 
-// ConfigPath returns the full path to config.json.
+// ConfigPath returns the full path to the active profile's config.json.
 func ConfigPath() (string, error) {
-	dir, err := ConfigDir()
+	return ConfigPathFor(ActiveProfile())
+}
+
+// ConfigPathFor returns the full path to a profile's config.json.
+func ConfigPathFor(profile string) (string, error) {
+	dir, err := ProfileDir(profile)
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(dir, configName), nil
 }
 
-// OrdersPath returns the full path to orders.json.
+// OrdersPath returns the full path to the active profile's orders.json.
 func OrdersPath() (string, error) {
-	dir, err := ConfigDir()
+	return OrdersPathFor(ActiveProfile())
+}
+
+// OrdersPathFor returns the full path to a profile's orders.json.
+func OrdersPathFor(profile string) (string, error) {
+	dir, err := ProfileDataDir(profile)
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(dir, ordersName), nil
 }
 
-// Load reads config.json if it exists.
+// Load reads the active profile's config.json if it exists and resolves the
+// session from the configured secret backend.
 func Load() (*Config, error) {
+	return LoadProfile(ActiveProfile())
+}
+
+// LoadProfile is Load for a named profile.
+func LoadProfile(profile string) (*Config, error) {
+	path, err := ConfigPathFor(profile)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Session != nil || cfg.SecretBackend == "" {
+		return cfg, nil
+	}
+	if cfg.SecretBackend == BackendEncrypted && !hasEncryptedSession(profile) {
+		// Logged out: nothing to decrypt, so no passphrase is needed.
+		return cfg, nil
+	}
+	backend, err := NewSecretBackend(cfg.SecretBackend)
+	if err != nil {
+		return nil, err
+	}
+	if backend == nil {
+		return cfg, nil
+	}
+	secret, err := backend.Get(sessionKey(profile))
+	if err != nil {
+		if errors.Is(err, ErrSecretNotFound) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(secret, &session); err != nil {
+		return nil, fmt.Errorf("decode session from %s: %w", backend.Name(), err)
+	}
+	cfg.Session = &session
+	return cfg, nil
+}
+
+// LoadFile reads the active profile's config.json without resolving the
+// session from the secret backend, for commands that only need the rest of
+// the config. Session is set only for a plaintext config.
+func LoadFile() (*Config, error) {
 	path, err := ConfigPath()
 	if err != nil {
 		return nil, err
 	}
+	return readConfigFile(path)
+}
+
+// Update applies update to the active profile's config.json and writes it
+// back. The secret backend is not involved, so update must not change the
+// session.
+func Update(update func(cfg *Config) error) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if err := update(cfg); err != nil {
+		return err
+	}
+	return writeConfigFile(path, cfg)
+}
+
+// Save writes the active profile's config.json with 0600 permissions. Unless
+// the plaintext backend is selected, the session goes to the secret backend
+// and is left out of the file; a config without a backend gets
+// DefaultSecretBackend.
+func Save(cfg *Config) error {
+	return SaveProfile(ActiveProfile(), cfg)
+}
+
+// SaveProfile is Save for a named profile.
+func SaveProfile(profile string, cfg *Config) error {
+	path, err := ConfigPathFor(profile)
+	if err != nil {
+		return err
+	}
+
+	onDisk := *cfg
+	if cfg.SecretBackend == "" && cfg.Session != nil {
+		name, err := DefaultSecretBackend()
+		if err != nil {
+			return err
+		}
+		cfg.SecretBackend = name
+		onDisk.SecretBackend = name
+	}
+	if cfg.SecretBackend != "" {
+		backend, err := NewSecretBackend(cfg.SecretBackend)
+		if err != nil {
+			return err
+		}
+		if backend != nil {
+			if err := saveSessionSecret(backend, sessionKey(profile), cfg.Session); err != nil {
+				return err
+			}
+			onDisk.Session = nil
+		}
+	}
+	return writeConfigFile(path, &onDisk)
+}
+
+func readConfigFile(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -89,12 +234,7 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// Save writes config.json with 0600 permissions.
-func Save(cfg *Config) error {
-	path, err := ConfigPath()
-	if err != nil {
-		return err
-	}
+func writeConfigFile(path string, cfg *Config) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
 		return err
 	}
@@ -105,18 +245,81 @@ func Save(cfg *Config) error {
 	return os.WriteFile(path, data, filePerm0600)
 }
 
-// Clear removes config.json if present.
+func saveSessionSecret(backend SecretBackend, key string, session *Session) error {
+	if session == nil {
+		if err := backend.Delete(key); err != nil && !errors.Is(err, ErrSecretNotFound) {
+			return err
+		}
+		return nil
+	}
+	secret, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return backend.Set(key, secret)
+}
+
+// Clear removes the active profile's session, if present.
 func Clear() error {
-	path, err := ConfigPath()
+	return ClearProfile(ActiveProfile())
+}
+
+// ClearProfile removes a profile's stored session. The session is not
+// decrypted, so this works without BLINKCLI_PASSPHRASE. The rest of
+// config.json (settings, budget, locations, rules, the backend choice) is
+// kept; the file is removed only when nothing else is left in it.
+func ClearProfile(profile string) error {
+	path, err := ConfigPathFor(profile)
 	if err != nil {
 		return err
 	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if cfg.SecretBackend != "" {
+		if err := deleteSessionSecret(cfg.SecretBackend, sessionKey(profile)); err != nil {
+			return err
+		}
+	}
+	cfg.Session = nil
+	if rest, err := json.Marshal(cfg); err != nil || string(rest) != "{}" {
+		return writeConfigFile(path, cfg)
+	}
 	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return err
 	}
 	return nil
 }
 
+// MissingFields lists the session fields the order endpoints expect that are
+// empty, using their JSON names.
+func (s *Session) MissingFields() []string {
+	if s == nil {
+		return []string{"access_token", "auth_key", "device_id", "session_uuid", "lat", "lon"}
+	}
+	var missing []string
+	if s.AccessToken == "" {
+		missing = append(missing, "access_token")
+	}
+	if s.AuthKey == "" {
+		missing = append(missing, "auth_key")
+	}
+	if s.DeviceID == "" {
+		missing = append(missing, "device_id")
+	}
+	if s.SessionID == "" {
+		missing = append(missing, "session_uuid")
+	}
+	if s.Lat == 0 {
+		missing = append(missing, "lat")
+	}
+	if s.Lon == 0 {
+		missing = append(missing, "lon")
+	}
+	return missing
+}
+
 // PopulateDerivedCookies seeds missing session cookies from known session fields.
 func PopulateDerivedCookies(session *Session) {
 	if session == nil {
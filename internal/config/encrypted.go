@@ -0,0 +1,131 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	encryptedVersion    = 1
+	defaultKDFIteration = 600_000
+	saltSize            = 16
+)
+
+// EncryptedFile stores each secret in its own passphrase-encrypted file
+// (PBKDF2-SHA256 key derivation, AES-256-GCM).
+type EncryptedFile struct {
+	Dir        string
+	Passphrase string
+	// Iterations defaults to 600k; lowered in tests.
+	Iterations int
+}
+
+type encryptedEnvelope struct {
+	Version    int    `json:"v"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iter"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+func (f *EncryptedFile) Name() string { return BackendEncrypted }
+
+func (f *EncryptedFile) Get(key string) ([]byte, error) {
+	raw, err := os.ReadFile(f.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, err
+	}
+	var env encryptedEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("read %s: %w", f.path(key), err)
+	}
+	if env.Version != encryptedVersion || env.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("read %s: unsupported format", f.path(key))
+	}
+	aead, err := f.aead(env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, env.Nonce, env.Data, []byte(key))
+	if err != nil {
+		return nil, errors.New("decrypt secret: wrong passphrase or corrupted file")
+	}
+	return plain, nil
+}
+
+func (f *EncryptedFile) Set(key string, value []byte) error {
+	iterations := f.Iterations
+	if iterations <= 0 {
+		iterations = defaultKDFIteration
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := f.aead(salt, iterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	env := encryptedEnvelope{
+		Version:    encryptedVersion,
+		KDF:        "pbkdf2-sha256",
+		Iterations: iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Data:       aead.Seal(nil, nonce, value, []byte(key)),
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path(key), data, filePerm0600)
+}
+
+func (f *EncryptedFile) Delete(key string) error {
+	if err := os.Remove(f.path(key)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrSecretNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (f *EncryptedFile) path(key string) string {
+	return filepath.Join(f.Dir, key+".enc")
+}
+
+func (f *EncryptedFile) aead(salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, f.Passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func secretsDir(configDir string) string {
+	return filepath.Join(configDir, "secrets")
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// osKeyring stores secrets in the login keychain via the security tool.
+type osKeyring struct{}
+
+// errItemNotFound is the exit status security uses for missing items.
+const errItemNotFound = 44
+
+func osKeyringAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func (osKeyring) Name() string { return BackendKeyring }
+
+func (osKeyring) Get(key string) ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", keyringService, "-a", key, "-w").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == errItemNotFound {
+			return nil, ErrSecretNotFound
+		}
+		return nil, keyringError("find-generic-password", err)
+	}
+	return bytes.TrimRight(out, "\n"), nil
+}
+
+func (osKeyring) Set(key string, value []byte) error {
+	// Run in interactive mode so the secret goes through stdin, not argv.
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		keyringService, key, hex.EncodeToString(value)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return keyringError("add-generic-password", errors.New(strings.TrimSpace(string(out))+" "+err.Error()))
+	}
+	return nil
+}
+
+func (osKeyring) Delete(key string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", keyringService, "-a", key).Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == errItemNotFound {
+			return ErrSecretNotFound
+		}
+		return keyringError("delete-generic-password", err)
+	}
+	return nil
+}
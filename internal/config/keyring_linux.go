@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// osKeyring talks to the Secret Service (GNOME Keyring, KWallet) through
+// libsecret's secret-tool.
+type osKeyring struct{}
+
+func osKeyringAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func (osKeyring) Name() string { return BackendKeyring }
+
+func (osKeyring) Get(key string) ([]byte, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", keyringService, "account", key).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) == 0 {
+			// secret-tool exits 1 without output when nothing matches.
+			return nil, ErrSecretNotFound
+		}
+		return nil, keyringError("lookup", err)
+	}
+	return out, nil
+}
+
+func (osKeyring) Set(key string, value []byte) error {
+	cmd := exec.Command("secret-tool", "store", "--label=blinkcli "+key, "service", keyringService, "account", key)
+	cmd.Stdin = bytes.NewReader(value)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return keyringError("store", errors.New(strings.TrimSpace(string(out))+" "+err.Error()))
+	}
+	return nil
+}
+
+func (osKeyring) Delete(key string) error {
+	if err := exec.Command("secret-tool", "clear", "service", keyringService, "account", key).Run(); err != nil {
+		return keyringError("clear", err)
+	}
+	return nil
+}
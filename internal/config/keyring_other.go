@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package config
+
+import "errors"
+
+// osKeyring is unavailable on this platform.
+type osKeyring struct{}
+
+func osKeyringAvailable() bool { return false }
+
+func (osKeyring) Name() string { return BackendKeyring }
+
+func (osKeyring) Get(string) ([]byte, error) { return nil, errors.New("keyring not supported") }
+
+func (osKeyring) Set(string, []byte) error { return errors.New("keyring not supported") }
+
+func (osKeyring) Delete(string) error { return errors.New("keyring not supported") }
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Location is a named delivery address. The session's lat/lon and gr_1_*
+// cookies are switched to it with ApplyLocation.
+type Location struct {
+	Name     string  `json:"name"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	Locality string  `json:"locality,omitempty"`
+	Landmark string  `json:"landmark,omitempty"`
+}
+
+// FindLocation looks a location up by name, case-insensitively.
+func (c *Config) FindLocation(name string) (Location, bool) {
+	for _, loc := range c.Locations {
+		if strings.EqualFold(loc.Name, name) {
+			return loc, true
+		}
+	}
+	return Location{}, false
+}
+
+// SetLocation adds loc, replacing a location with the same name.
+func (c *Config) SetLocation(loc Location) {
+	for i, existing := range c.Locations {
+		if strings.EqualFold(existing.Name, loc.Name) {
+			c.Locations[i] = loc
+			return
+		}
+	}
+	c.Locations = append(c.Locations, loc)
+}
+
+// RemoveLocation deletes a location and reports whether it existed.
+func (c *Config) RemoveLocation(name string) bool {
+	for i, loc := range c.Locations {
+		if strings.EqualFold(loc.Name, name) {
+			c.Locations = append(c.Locations[:i], c.Locations[i+1:]...)
+			if strings.EqualFold(c.DefaultLocation, name) {
+				c.DefaultLocation = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyLocation points the session at the named location, or at
+// DefaultLocation when name is empty, and returns the name applied. With no
+// name and no default the session is left as captured and "" is returned,
+// unless its coordinates match a saved location.
+func (c *Config) ApplyLocation(name string) (string, error) {
+	if c.Session == nil {
+		return "", fmt.Errorf("not logged in")
+	}
+	if name == "" {
+		name = c.DefaultLocation
+	}
+	if name == "" {
+		for _, loc := range c.Locations {
+			if loc.Lat == c.Session.Lat && loc.Lon == c.Session.Lon {
+				return loc.Name, nil
+			}
+		}
+		return "", nil
+	}
+	loc, ok := c.FindLocation(name)
+	if !ok {
+		return "", fmt.Errorf("unknown location %q (see 'blinkcli location list')", name)
+	}
+
+	s := c.Session
+	s.Lat, s.Lon = loc.Lat, loc.Lon
+	s.Locality, s.Landmark = loc.Locality, loc.Landmark
+	if s.Cookies == nil {
+		s.Cookies = map[string]string{}
+	}
+	// PopulateDerivedCookies only fills gaps; location cookies must follow.
+	for _, name := range []string{"gr_1_lat", "gr_1_lon", "gr_1_locality", "gr_1_landmark"} {
+		delete(s.Cookies, name)
+	}
+	PopulateDerivedCookies(s)
+	return loc.Name, nil
+}
+
+// SessionLocation returns the session's current address as a Location.
+func SessionLocation(name string, s *Session) Location {
+	return Location{Name: name, Lat: s.Lat, Lon: s.Lon, Locality: s.Locality, Landmark: s.Landmark}
+}
@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestApplyLocation(t *testing.T) {
+	cfg := &Config{
+		Session: &Session{
+			Lat:     28.1,
+			Lon:     77.1,
+			Cookies: map[string]string{"gr_1_lat": "28.100000", "gr_1_lon": "77.100000"},
+		},
+		Locations: []Location{
+			{Name: "home", Lat: 28.1, Lon: 77.1},
+			{Name: "office", Lat: 12.9, Lon: 77.6, Locality: "Koramangala"},
+		},
+	}
+
+	name, err := cfg.ApplyLocation("")
+	if err != nil || name != "home" {
+		t.Fatalf("expected session coordinates to match home, got %q, %v", name, err)
+	}
+
+	name, err = cfg.ApplyLocation("Office")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if name != "office" || cfg.Session.Lat != 12.9 || cfg.Session.Locality != "Koramangala" {
+		t.Fatalf("unexpected session after apply: %q %+v", name, cfg.Session)
+	}
+	if cfg.Session.Cookies["gr_1_lat"] != "12.900000" || cfg.Session.Cookies["gr_1_locality"] != "Koramangala" {
+		t.Fatalf("expected location cookies to follow, got %v", cfg.Session.Cookies)
+	}
+
+	if _, err := cfg.ApplyLocation("gym"); err == nil {
+		t.Fatalf("expected unknown location to fail")
+	}
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile keeps its files directly in the config directory, so
+// configs from before profiles existed keep working.
+const DefaultProfile = "default"
+
+// ProfileEnv selects the profile when --profile isn't given.
+const ProfileEnv = "BLINKCLI_PROFILE"
+
+const profilesDirName = "profiles"
+
+var (
+	profileNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+	// activeProfile is set from the --profile flag.
+	activeProfile string
+)
+
+// SetProfile overrides the active profile for this process. The profile must
+// already exist; only AddProfile creates one, so a misspelled name is an
+// error rather than a new, empty profile.
+func SetProfile(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	profiles, err := ListProfiles()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(profiles, name) {
+		return fmt.Errorf("unknown profile %q (profiles: %s; create it with 'blinkcli profile add %s')", name, strings.Join(profiles, ", "), name)
+	}
+	activeProfile = name
+	return nil
+}
+
+// ActiveProfile resolves the profile in use: --profile, then
+// BLINKCLI_PROFILE, then the current profile saved by 'profile use'.
+func ActiveProfile() string {
+	if activeProfile != "" {
+		return activeProfile
+	}
+	if env := os.Getenv(ProfileEnv); env != "" && ValidateProfileName(env) == nil {
+		return env
+	}
+	if path, err := ConfigPathFor(DefaultProfile); err == nil {
+		if cfg, err := readConfigFile(path); err == nil && ValidateProfileName(cfg.CurrentProfile) == nil {
+			return cfg.CurrentProfile
+		}
+	}
+	return DefaultProfile
+}
+
+// ValidateProfileName checks that name is usable as a directory name.
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ProfileDir returns the directory holding a profile's files.
+func ProfileDir(profile string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" || profile == DefaultProfile {
+		return dir, nil
+	}
+	if err := ValidateProfileName(profile); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, profilesDirName, profile), nil
+}
+
+// ProfileDataDir returns the directory holding a profile's order store.
+func ProfileDataDir(profile string) (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" || profile == DefaultProfile {
+		return dir, nil
+	}
+	if err := ValidateProfileName(profile); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, profilesDirName, profile), nil
+}
+
+// ListProfiles returns all profile names, default first.
+func ListProfiles() ([]string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, profilesDirName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProfileName(entry.Name()) == nil && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// ProfileExists reports whether a profile has been created.
+func ProfileExists(profile string) bool {
+	if profile == DefaultProfile {
+		return true
+	}
+	dir, err := ProfileDir(profile)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// AddProfile creates an empty profile.
+func AddProfile(profile string) error {
+	if err := ValidateProfileName(profile); err != nil {
+		return err
+	}
+	if ProfileExists(profile) {
+		return fmt.Errorf("profile %q already exists", profile)
+	}
+	dir, err := ProfileDir(profile)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0o700)
+}
+
+// RemoveProfile deletes a profile's session, config and orders. The default
+// profile can't be removed.
+func RemoveProfile(profile string) error {
+	if profile == DefaultProfile {
+		return errors.New("the default profile can't be removed")
+	}
+	if !ProfileExists(profile) {
+		return fmt.Errorf("profile %q does not exist", profile)
+	}
+	if err := ClearProfile(profile); err != nil {
+		return err
+	}
+	dir, err := ProfileDir(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	dataDir, err := ProfileDataDir(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dataDir); err != nil {
+		return err
+	}
+	return updateCurrentProfile(func(current string) string {
+		if current == profile {
+			return ""
+		}
+		return current
+	})
+}
+
+// UseProfile makes profile the current one for future invocations.
+func UseProfile(profile string) error {
+	if !ProfileExists(profile) {
+		return fmt.Errorf("profile %q does not exist (create it with 'blinkcli profile add %s')", profile, profile)
+	}
+	return updateCurrentProfile(func(string) string {
+		if profile == DefaultProfile {
+			return ""
+		}
+		return profile
+	})
+}
+
+// updateCurrentProfile rewrites current_profile in the default profile's
+// config.json without touching its session.
+func updateCurrentProfile(update func(current string) string) error {
+	path, err := ConfigPathFor(DefaultProfile)
+	if err != nil {
+		return err
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	next := update(cfg.CurrentProfile)
+	if next == cfg.CurrentProfile {
+		return nil
+	}
+	cfg.CurrentProfile = next
+	return writeConfigFile(path, cfg)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ProfileEnv, "")
+	t.Cleanup(func() { activeProfile = "" })
+
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Fatalf("expected default profile, got %q", got)
+	}
+	if err := AddProfile("work"); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := AddProfile("Bad Name"); err == nil {
+		t.Fatalf("expected invalid name to fail")
+	}
+	if err := UseProfile("work"); err != nil {
+		t.Fatalf("use failed: %v", err)
+	}
+	if got := ActiveProfile(); got != "work" {
+		t.Fatalf("expected work profile, got %q", got)
+	}
+
+	path, err := OrdersPath()
+	if err != nil {
+		t.Fatalf("orders path failed: %v", err)
+	}
+	if !strings.HasSuffix(path, filepath.Join("profiles", "work", "orders.json")) {
+		t.Fatalf("unexpected orders path %s", path)
+	}
+
+	t.Setenv(ProfileEnv, "default")
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Fatalf("expected env to win over current profile, got %q", got)
+	}
+	if err := SetProfile("work"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if got := ActiveProfile(); got != "work" {
+		t.Fatalf("expected flag to win over env, got %q", got)
+	}
+	if err := SetProfile("wrok"); err == nil || ActiveProfile() != "work" {
+		t.Fatalf("expected an unknown profile to be rejected, got %v", err)
+	}
+	if ProfileExists("wrok") {
+		t.Fatalf("expected no directory for an unknown profile")
+	}
+
+	profiles, err := ListProfiles()
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if strings.Join(profiles, ",") != "default,work" {
+		t.Fatalf("unexpected profiles %v", profiles)
+	}
+
+	if err := RemoveProfile("work"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	activeProfile = ""
+	t.Setenv(ProfileEnv, "")
+	if got := ActiveProfile(); got != DefaultProfile {
+		t.Fatalf("expected removal to reset current profile, got %q", got)
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Secret backend names stored in Config.SecretBackend.
+const (
+	BackendPlaintext = "plaintext"
+	BackendKeyring   = "keyring"
+	BackendEncrypted = "encrypted-file"
+)
+
+// PassphraseEnv holds the passphrase for the encrypted-file backend.
+const PassphraseEnv = "BLINKCLI_PASSPHRASE"
+
+const keyringService = "blinkcli"
+
+// keyringAvailable is a variable so tests never touch the real keyring.
+var keyringAvailable = osKeyringAvailable
+
+// ErrSecretNotFound is returned by SecretBackend.Get for unknown keys.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretBackend stores session secrets outside config.json.
+type SecretBackend interface {
+	Name() string
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte) error
+	Delete(key string) error
+}
+
+// SecretBackends lists the backend names accepted by NewSecretBackend.
+func SecretBackends() []string {
+	return []string{BackendKeyring, BackendEncrypted, BackendPlaintext}
+}
+
+// NewSecretBackend returns the named backend. The plaintext backend has no
+// store of its own: the session stays inline in config.json, so it returns nil.
+func NewSecretBackend(name string) (SecretBackend, error) {
+	switch name {
+	case BackendPlaintext:
+		return nil, nil
+	case BackendKeyring:
+		if !keyringAvailable() {
+			return nil, errors.New("no OS keyring available (needs secret-tool with a D-Bus session on Linux, or macOS)")
+		}
+		return osKeyring{}, nil
+	case BackendEncrypted:
+		dir, err := ConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		passphrase := os.Getenv(PassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("%s must be set to use the %s backend", PassphraseEnv, BackendEncrypted)
+		}
+		return &EncryptedFile{Dir: secretsDir(dir), Passphrase: passphrase}, nil
+	default:
+		return nil, fmt.Errorf("unknown secret backend %q (choose %s)", name, strings.Join(SecretBackends(), ", "))
+	}
+}
+
+// DefaultSecretBackend picks the backend for a config that hasn't chosen one:
+// the OS keyring when available, else the encrypted file when a passphrase is
+// set. Plaintext is never picked implicitly.
+func DefaultSecretBackend() (string, error) {
+	if keyringAvailable() {
+		return BackendKeyring, nil
+	}
+	if os.Getenv(PassphraseEnv) != "" {
+		return BackendEncrypted, nil
+	}
+	return "", fmt.Errorf("no secret backend available: set %s, or opt in to plaintext with 'blinkcli config migrate-secrets --backend %s'", PassphraseEnv, BackendPlaintext)
+}
+
+// CheckSecretBackend reports an error when cfg's session could not be saved:
+// its backend is unavailable, or it has none and no default is available.
+// Logins call it first so a session isn't captured only to be thrown away.
+func CheckSecretBackend(cfg *Config) error {
+	name := cfg.SecretBackend
+	if name == "" {
+		var err error
+		if name, err = DefaultSecretBackend(); err != nil {
+			return fmt.Errorf("cannot save a session: %w", err)
+		}
+	}
+	if _, err := NewSecretBackend(name); err != nil {
+		return fmt.Errorf("cannot save a session: %w", err)
+	}
+	return nil
+}
+
+// deleteSessionSecret removes a stored session from the named backend. The
+// encrypted file is removed without its passphrase, so a session can be
+// cleared without knowing it.
+func deleteSessionSecret(name, key string) error {
+	var backend SecretBackend
+	if name == BackendEncrypted {
+		dir, err := ConfigDir()
+		if err != nil {
+			return err
+		}
+		backend = &EncryptedFile{Dir: secretsDir(dir)}
+	} else {
+		b, err := NewSecretBackend(name)
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return nil
+		}
+		backend = b
+	}
+	return saveSessionSecret(backend, key, nil)
+}
+
+// hasEncryptedSession reports whether the encrypted file backend holds a
+// session for profile.
+func hasEncryptedSession(profile string) bool {
+	dir, err := ConfigDir()
+	if err != nil {
+		return true
+	}
+	_, err = os.Stat((&EncryptedFile{Dir: secretsDir(dir)}).path(sessionKey(profile)))
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func keyringError(op string, err error) error {
+	return fmt.Errorf("keyring %s: %w", op, err)
+}
+
+// sessionKey names a profile's session in the secret backend.
+func sessionKey(profile string) string {
+	if profile == DefaultProfile {
+		return "session"
+	}
+	return "session-" + profile
+}
+
+// MigrateSecrets moves the active profile's stored session to the named
+// backend and records the choice in its config.json.
+func MigrateSecrets(to string) error {
+	if _, err := NewSecretBackend(to); err != nil {
+		return err
+	}
+	profile := ActiveProfile()
+	cfg, err := LoadProfile(profile)
+	if err != nil {
+		return err
+	}
+	from := cfg.SecretBackend
+	cfg.SecretBackend = to
+	if err := SaveProfile(profile, cfg); err != nil {
+		return err
+	}
+	if from == "" || from == BackendPlaintext || from == to {
+		return nil
+	}
+	old, err := NewSecretBackend(from)
+	if err != nil {
+		return err
+	}
+	if err := old.Delete(sessionKey(profile)); err != nil && !errors.Is(err, ErrSecretNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	backend := &EncryptedFile{Dir: dir, Passphrase: "correct horse", Iterations: 1000}
+
+	if err := backend.Set("session", []byte(`{"access_token":"tok"}`)); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "session.enc"))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if strings.Contains(string(raw), "tok") {
+		t.Fatalf("expected ciphertext, found plaintext token in %s", raw)
+	}
+
+	got, err := backend.Get("session")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if string(got) != `{"access_token":"tok"}` {
+		t.Fatalf("unexpected secret: %s", got)
+	}
+
+	wrong := &EncryptedFile{Dir: dir, Passphrase: "wrong"}
+	if _, err := wrong.Get("session"); err == nil {
+		t.Fatalf("expected wrong passphrase to fail")
+	}
+
+	if err := backend.Delete("session"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := backend.Get("session"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound, got %v", err)
+	}
+}
+
+func TestSaveKeepsSessionOutOfConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	withoutKeyring(t)
+	t.Setenv(PassphraseEnv, "pass")
+
+	cfg := &Config{Session: &Session{AccessToken: "tok", DeviceID: "dev"}}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if cfg.SecretBackend != BackendEncrypted {
+		t.Fatalf("expected encrypted backend, got %q", cfg.SecretBackend)
+	}
+	path, _ := ConfigPath()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if strings.Contains(string(raw), "tok") {
+		t.Fatalf("expected no token in config.json, got %s", raw)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if loaded.Session == nil || loaded.Session.AccessToken != "tok" {
+		t.Fatalf("expected session from backend, got %+v", loaded.Session)
+	}
+}
+
+func TestSaveWithoutBackendFails(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	withoutKeyring(t)
+	t.Setenv(PassphraseEnv, "")
+
+	if err := Save(&Config{Session: &Session{AccessToken: "tok"}}); err == nil {
+		t.Fatalf("expected error without an available backend")
+	}
+	if err := Save(&Config{Session: &Session{AccessToken: "tok"}, SecretBackend: BackendPlaintext}); err != nil {
+		t.Fatalf("expected explicit plaintext to work, got %v", err)
+	}
+}
+
+func TestCheckSecretBackend(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	withoutKeyring(t)
+	t.Setenv(PassphraseEnv, "")
+
+	if err := CheckSecretBackend(&Config{}); err == nil {
+		t.Fatalf("expected error without an available backend")
+	}
+	if err := CheckSecretBackend(&Config{SecretBackend: BackendEncrypted}); err == nil {
+		t.Fatalf("expected error for the encrypted backend without a passphrase")
+	}
+	if err := CheckSecretBackend(&Config{SecretBackend: BackendPlaintext}); err != nil {
+		t.Fatalf("expected plaintext to be usable, got %v", err)
+	}
+	t.Setenv(PassphraseEnv, "pass")
+	if err := CheckSecretBackend(&Config{}); err != nil {
+		t.Fatalf("expected the encrypted file as default, got %v", err)
+	}
+}
+
+func TestClearWithoutPassphrase(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	withoutKeyring(t)
+	t.Setenv(PassphraseEnv, "pass")
+	if err := Save(&Config{Session: &Session{AccessToken: "tok"}}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	t.Setenv(PassphraseEnv, "")
+	if err := Clear(); err != nil {
+		t.Fatalf("expected clear to work without the passphrase, got %v", err)
+	}
+	dir, _ := ConfigDir()
+	if _, err := os.Stat(filepath.Join(secretsDir(dir), "session.enc")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the encrypted session to be removed, got %v", err)
+	}
+	cfg, err := Load()
+	if err != nil || cfg.Session != nil {
+		t.Fatalf("expected no session after clear, got %+v (%v)", cfg.Session, err)
+	}
+}
+
+func TestClearKeepsConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	withoutKeyring(t)
+	t.Setenv(PassphraseEnv, "pass")
+	cfg := &Config{
+		Session:         &Session{AccessToken: "tok"},
+		Settings:        map[string]string{"output": "json"},
+		Budget:          &Budget{Monthly: 5000},
+		Locations:       []Location{{Name: "home", Lat: 12.9, Lon: 77.6}},
+		DefaultLocation: "home",
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	if err := Clear(); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	got, err := Load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if got.Session != nil {
+		t.Fatalf("expected the session to be cleared, got %+v", got.Session)
+	}
+	if got.Settings["output"] != "json" || got.Budget == nil || got.Budget.Monthly != 5000 ||
+		len(got.Locations) != 1 || got.DefaultLocation != "home" || got.SecretBackend != BackendEncrypted {
+		t.Fatalf("expected the rest of the config to be kept, got %+v", got)
+	}
+
+	if err := Save(&Config{Session: &Session{AccessToken: "tok"}, SecretBackend: BackendPlaintext}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if err := Clear(); err != nil {
+		t.Fatalf("clear failed: %v", err)
+	}
+	path, _ := ConfigPath()
+	raw, _ := os.ReadFile(path)
+	if strings.Contains(string(raw), "tok") {
+		t.Fatalf("expected no inline session after clear, got %s", raw)
+	}
+}
+
+func TestUpdateWithoutSecretBackend(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	withoutKeyring(t)
+	t.Setenv(PassphraseEnv, "pass")
+	if err := Save(&Config{Session: &Session{AccessToken: "tok"}}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	t.Setenv(PassphraseEnv, "")
+	err := Update(func(cfg *Config) error {
+		cfg.Budget = &Budget{Monthly: 3000}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected update to work without the passphrase, got %v", err)
+	}
+	if cfg, err := LoadFile(); err != nil || cfg.Budget == nil || cfg.Budget.Monthly != 3000 {
+		t.Fatalf("expected the budget to be saved, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv(PassphraseEnv, "pass")
+	cfg, err := Load()
+	if err != nil || cfg.Session == nil || cfg.Session.AccessToken != "tok" || cfg.SecretBackend != BackendEncrypted {
+		t.Fatalf("expected the session to be untouched, got %+v (%v)", cfg, err)
+	}
+}
+
+func withoutKeyring(t *testing.T) {
+	t.Helper()
+	prev := keyringAvailable
+	keyringAvailable = func() bool { return false }
+	t.Cleanup(func() { keyringAvailable = prev })
+}
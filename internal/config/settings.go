@@ -0,0 +1,262 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Setting sources, from highest to lowest precedence after command flags.
+const (
+	SourceEnv     = "env"
+	SourceFile    = "file"
+	SourceDefault = "default"
+)
+
+// Setting describes a user-configurable default.
+type Setting struct {
+	Key     string
+	Env     string
+	Default string
+	Help    string
+	check   func(string) error
+}
+
+// SettingValue is a setting resolved against the environment and config.json.
+type SettingValue struct {
+	Setting
+	Value  string
+	Source string
+}
+
+var settingDefs = []Setting{
+	{Key: "sync.pages", Env: "BLINKCLI_SYNC_PAGES", Default: "1", Help: "max pages fetched by sync", check: checkInt(1)},
+	{Key: "sync.page_size", Env: "BLINKCLI_SYNC_PAGE_SIZE", Default: "0", Help: "page size sent by sync (0 = API default)", check: checkInt(0)},
+	{Key: "sync.sleep_ms", Env: "BLINKCLI_SYNC_SLEEP_MS", Default: "350", Help: "pause between sync pages in milliseconds", check: checkInt(0)},
+	{Key: "output", Env: "BLINKCLI_OUTPUT", Default: "table", Help: "output format for orders and stats", check: checkOneOf("table", "json", "ndjson", "csv", "tsv", "markdown")},
+	{Key: "stats.small_order", Env: "BLINKCLI_STATS_SMALL_ORDER", Default: "199", Help: "order value in rupees below which stats counts an order as small (likely with a delivery fee)", check: checkInt(0)},
+	{Key: "timezone", Env: "BLINKCLI_TIMEZONE", Default: "Local", Help: "IANA time zone for parsing and showing dates", check: checkTimeZone},
+	{Key: "data_dir", Env: "BLINKCLI_DATA_DIR", Default: "", Help: "directory for orders.json (default: $XDG_DATA_HOME/blinkcli or the config directory)", check: checkDir},
+}
+
+// Settings lists every known setting with its resolved value.
+func Settings() ([]SettingValue, error) {
+	file, err := fileSettings()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]SettingValue, 0, len(settingDefs))
+	for _, def := range settingDefs {
+		values = append(values, resolveSetting(def, file))
+	}
+	return values, nil
+}
+
+// GetSetting resolves one setting: environment, then config.json, then default.
+func GetSetting(key string) (SettingValue, error) {
+	def, err := findSetting(key)
+	if err != nil {
+		return SettingValue{}, err
+	}
+	file, err := fileSettings()
+	if err != nil {
+		return SettingValue{}, err
+	}
+	value := resolveSetting(def, file)
+	if value.Source == SourceEnv {
+		if err := def.check(value.Value); err != nil {
+			return SettingValue{}, fmt.Errorf("%s: %w", def.Env, err)
+		}
+	}
+	return value, nil
+}
+
+// SettingString returns a setting's resolved value.
+func SettingString(key string) (string, error) {
+	value, err := GetSetting(key)
+	if err != nil {
+		return "", err
+	}
+	return value.Value, nil
+}
+
+// SettingInt returns a setting's resolved value as an int.
+func SettingInt(key string) (int, error) {
+	value, err := SettingString(key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// SetSetting validates value and stores it in the default profile's config.json.
+// An empty value removes the setting.
+func SetSetting(key, value string) error {
+	def, err := findSetting(key)
+	if err != nil {
+		return err
+	}
+	if value != "" {
+		if err := def.check(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	path, err := ConfigPathFor(DefaultProfile)
+	if err != nil {
+		return err
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		delete(cfg.Settings, key)
+	} else {
+		if cfg.Settings == nil {
+			cfg.Settings = map[string]string{}
+		}
+		cfg.Settings[key] = value
+	}
+	return writeConfigFile(path, cfg)
+}
+
+// SettingKeys returns the known setting keys in order.
+func SettingKeys() []string {
+	keys := make([]string, 0, len(settingDefs))
+	for _, def := range settingDefs {
+		keys = append(keys, def.Key)
+	}
+	return keys
+}
+
+// TimeZone returns the configured time zone.
+func TimeZone() (*time.Location, error) {
+	name, err := SettingString("timezone")
+	if err != nil {
+		return nil, err
+	}
+	return time.LoadLocation(name)
+}
+
+// DataDir returns the directory holding order stores: the data_dir setting,
+// else $XDG_DATA_HOME/blinkcli, else the config directory. An existing
+// orders.json in the config directory, the default profile's or a named
+// profile's, keeps it in use.
+func DataDir() (string, error) {
+	dir, err := SettingString("data_dir")
+	if err != nil {
+		return "", err
+	}
+	if dir != "" {
+		return expandHome(dir)
+	}
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	xdg := os.Getenv("XDG_DATA_HOME")
+	if xdg == "" {
+		return configDir, nil
+	}
+	if hasStores(configDir) {
+		return configDir, nil
+	}
+	return filepath.Join(xdg, appDirName), nil
+}
+
+// hasStores reports whether dir holds the order store of any profile.
+func hasStores(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ordersName)); err == nil {
+		return true
+	}
+	stores, _ := filepath.Glob(filepath.Join(dir, profilesDirName, "*", ordersName))
+	return len(stores) > 0
+}
+
+func findSetting(key string) (Setting, error) {
+	for _, def := range settingDefs {
+		if def.Key == key {
+			return def, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown setting %q (known: %s)", key, strings.Join(SettingKeys(), ", "))
+}
+
+func resolveSetting(def Setting, file map[string]string) SettingValue {
+	if env, ok := os.LookupEnv(def.Env); ok && env != "" {
+		return SettingValue{Setting: def, Value: env, Source: SourceEnv}
+	}
+	if value, ok := file[def.Key]; ok {
+		return SettingValue{Setting: def, Value: value, Source: SourceFile}
+	}
+	return SettingValue{Setting: def, Value: def.Default, Source: SourceDefault}
+}
+
+func fileSettings() (map[string]string, error) {
+	path, err := ConfigPathFor(DefaultProfile)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Settings, nil
+}
+
+func expandHome(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path, nil
+}
+
+func checkInt(min int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		if n < min {
+			return fmt.Errorf("must be at least %d", min)
+		}
+		return nil
+	}
+}
+
+func checkOneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		sorted := append([]string(nil), allowed...)
+		sort.Strings(sorted)
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(sorted, ", "))
+	}
+}
+
+func checkTimeZone(value string) error {
+	_, err := time.LoadLocation(value)
+	return err
+}
+
+func checkDir(value string) error {
+	path, err := expandHome(value)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%q is not an absolute path", value)
+	}
+	return nil
+}
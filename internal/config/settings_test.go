@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettingPrecedence(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLINKCLI_SYNC_PAGES", "")
+
+	value, err := GetSetting("sync.pages")
+	if err != nil || value.Value != "1" || value.Source != SourceDefault {
+		t.Fatalf("expected default, got %+v, %v", value, err)
+	}
+
+	if err := SetSetting("sync.pages", "3"); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if err := SetSetting("sync.pages", "0"); err == nil {
+		t.Fatalf("expected invalid value to fail")
+	}
+	if n, err := SettingInt("sync.pages"); err != nil || n != 3 {
+		t.Fatalf("expected file value 3, got %d, %v", n, err)
+	}
+
+	t.Setenv("BLINKCLI_SYNC_PAGES", "5")
+	value, err = GetSetting("sync.pages")
+	if err != nil || value.Value != "5" || value.Source != SourceEnv {
+		t.Fatalf("expected env to win, got %+v, %v", value, err)
+	}
+
+	if _, err := GetSetting("nope"); err == nil {
+		t.Fatalf("expected unknown key to fail")
+	}
+}
+
+func TestDataDir(t *testing.T) {
+	configHome := t.TempDir()
+	dataHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLINKCLI_DATA_DIR", "")
+
+	dir, err := DataDir()
+	if err != nil || dir != filepath.Join(dataHome, "blinkcli") {
+		t.Fatalf("expected XDG data dir, got %s, %v", dir, err)
+	}
+
+	legacy := filepath.Join(configHome, "blinkcli")
+	if err := os.MkdirAll(legacy, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "orders.json"), []byte("[]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if dir, _ := DataDir(); dir != legacy {
+		t.Fatalf("expected existing orders.json to keep config dir, got %s", dir)
+	}
+
+	custom := t.TempDir()
+	t.Setenv("BLINKCLI_DATA_DIR", custom)
+	if dir, _ := DataDir(); dir != custom {
+		t.Fatalf("expected env override, got %s", dir)
+	}
+}
+
+func TestDataDirKeepsProfileStores(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("BLINKCLI_DATA_DIR", "")
+
+	// Only a named profile has synced; the default store doesn't exist.
+	work := filepath.Join(configHome, "blinkcli", "profiles", "work")
+	if err := os.MkdirAll(work, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "orders.json"), []byte("[]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if dir, _ := DataDir(); dir != filepath.Join(configHome, "blinkcli") {
+		t.Fatalf("expected a profile's orders.json to keep the config dir, got %s", dir)
+	}
+}
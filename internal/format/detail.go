@@ -0,0 +1,133 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+// OrderDetailRecord is the machine-readable form of 'orders show': the
+// order record plus the raw, link and sync fields. Times are null when
+// unknown.
+type OrderDetailRecord struct {
+	OrderRecord
+	RawDate       string     `json:"raw_date"`
+	Deeplink      string     `json:"deeplink"`
+	WebURL        string     `json:"web_url"`
+	FirstSyncedAt *time.Time `json:"first_synced_at"`
+	LastSyncedAt  *time.Time `json:"last_synced_at"`
+	SyncCount     int        `json:"sync_count"`
+}
+
+// NewOrderDetailRecord converts an order for detailed json output.
+func NewOrderDetailRecord(order blink.Order) OrderDetailRecord {
+	return OrderDetailRecord{
+		OrderRecord:   NewOrderRecord(order),
+		RawDate:       order.RawDate,
+		Deeplink:      order.Deeplink,
+		WebURL:        blink.OrderWebURL(order.ID),
+		FirstSyncedAt: timePtr(order.FirstSyncedAt),
+		LastSyncedAt:  timePtr(order.LastSyncedAt),
+		SyncCount:     order.SyncCount,
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// OrderDetail bundles every rendering of a single order.
+func OrderDetail(order blink.Order) Output {
+	record := NewOrderDetailRecord(order)
+	return Output{
+		Text:    func() string { return OrderDetailText(order) },
+		Value:   record,
+		Records: []any{record},
+		Rows:    func() Tabular { return orderDetailRows(record) },
+	}
+}
+
+// OrderDetailText lists every field of an order, its full item list and
+// when it was synced.
+func OrderDetailText(order blink.Order) string {
+	fields := [][2]string{
+		{"Status", order.Status},
+		{"Title", order.Title},
+		{"Date", detailDate(order)},
+		{"Amount", Rupees(order.AmountRupees)},
+		{"Cart ID", order.CartID},
+		{"Synced under", order.Location},
+		{"Synced", syncSummary(order)},
+		{"App link", order.Deeplink},
+		{"Web", blink.OrderWebURL(order.ID)},
+	}
+	lines := []string{"Order " + order.ID}
+	for _, f := range fields {
+		value := f[1]
+		if value == "" {
+			value = "-"
+		}
+		lines = append(lines, fmt.Sprintf("  %-9s %s", f[0]+":", value))
+	}
+	lines = append(lines, fmt.Sprintf("  Items (%d):", len(order.Items)))
+	for i, item := range order.Items {
+		lines = append(lines, fmt.Sprintf("    %2d. %s", i+1, item))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func detailDate(order blink.Order) string {
+	date := RelativeDate(order.Date)
+	switch {
+	case date == "":
+		return order.RawDate
+	case order.RawDate != "" && order.RawDate != date:
+		return fmt.Sprintf("%s (shown as %q)", date, order.RawDate)
+	}
+	return date
+}
+
+func syncSummary(order blink.Order) string {
+	if order.SyncCount == 0 {
+		return ""
+	}
+	times := "once"
+	if order.SyncCount > 1 {
+		times = fmt.Sprintf("%d times", order.SyncCount)
+	}
+	return fmt.Sprintf("%s, first %s, last %s", times, RelativeDate(order.FirstSyncedAt), RelativeDate(order.LastSyncedAt))
+}
+
+func orderDetailRows(r OrderDetailRecord) Tabular {
+	timeText := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	return Tabular{
+		Header: []string{"field", "value"},
+		Rows: [][]string{
+			{"id", r.ID},
+			{"cart_id", r.CartID},
+			{"date", timeText(r.Date)},
+			{"raw_date", r.RawDate},
+			{"amount_rupees", strconv.Itoa(r.AmountRupees)},
+			{"status", r.Status},
+			{"title", r.Title},
+			{"items", strings.Join(r.Items, "; ")},
+			{"location", r.Location},
+			{"deeplink", r.Deeplink},
+			{"web_url", r.WebURL},
+			{"first_synced_at", timeText(r.FirstSyncedAt)},
+			{"last_synced_at", timeText(r.LastSyncedAt)},
+			{"sync_count", strconv.Itoa(r.SyncCount)},
+		},
+	}
+}
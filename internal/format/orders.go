@@ -2,29 +2,238 @@ package format
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"blinkcli/internal/blink"
 )
 
-// OrdersTable renders orders in a compact, line-based format.
-func OrdersTable(orders []blink.Order) string {
-	lines := make([]string, 0, len(orders)+1)
-	lines = append(lines, "DATE | AMOUNT | ORDER ID | ITEMS")
+// OrderRecord is the machine-readable form of an order. Every field is
+// always present; see docs/output-schema.md.
+type OrderRecord struct {
+	ID           string     `json:"id"`
+	CartID       string     `json:"cart_id"`
+	Date         *time.Time `json:"date"`
+	AmountRupees int        `json:"amount_rupees"`
+	Status       string     `json:"status"`
+	Title        string     `json:"title"`
+	Items        []string   `json:"items"`
+	Location     string     `json:"location"`
+}
+
+// NewOrderRecord converts an order for json and ndjson output.
+func NewOrderRecord(order blink.Order) OrderRecord {
+	record := OrderRecord{
+		ID:           order.ID,
+		CartID:       order.CartID,
+		AmountRupees: order.AmountRupees,
+		Status:       order.Status,
+		Title:        order.Title,
+		Items:        append([]string{}, order.Items...),
+		Location:     order.Location,
+	}
+	if !order.Date.IsZero() {
+		date := order.Date
+		record.Date = &date
+	}
+	return record
+}
+
+// orderColumn renders one order field in tables and delimited output.
+type orderColumn struct {
+	Column
+	// field is the csv/tsv/markdown header.
+	field string
+	text  func(blink.Order) string
+	raw   func(blink.Order) string
+}
+
+var orderColumns = map[string]orderColumn{
+	"date": {
+		Column: Column{Header: "DATE"},
+		field:  "date",
+		text:   func(o blink.Order) string { return RelativeDate(o.Date) },
+		raw: func(o blink.Order) string {
+			if o.Date.IsZero() {
+				return ""
+			}
+			return o.Date.Format(time.RFC3339)
+		},
+	},
+	"amount": {
+		Column: Column{Header: "AMOUNT", Right: true},
+		field:  "amount_rupees",
+		text:   func(o blink.Order) string { return fmt.Sprintf("₹%d", o.AmountRupees) },
+		raw:    func(o blink.Order) string { return strconv.Itoa(o.AmountRupees) },
+	},
+	"status": {
+		Column: Column{Header: "STATUS"},
+		field:  "status",
+		text:   func(o blink.Order) string { return o.Status },
+	},
+	"items": {
+		Column: Column{Header: "ITEMS", Wrap: true},
+		field:  "items",
+		text:   func(o blink.Order) string { return strings.Join(o.Items, ", ") },
+		raw:    func(o blink.Order) string { return strings.Join(o.Items, "; ") },
+	},
+	"id": {
+		Column: Column{Header: "ORDER ID"},
+		field:  "id",
+		text:   func(o blink.Order) string { return o.ID },
+	},
+	"location": {
+		Column: Column{Header: "SYNCED UNDER"},
+		field:  "location",
+		text:   func(o blink.Order) string { return o.Location },
+	},
+	"title": {
+		Column: Column{Header: "TITLE", Wrap: true},
+		field:  "title",
+		text:   func(o blink.Order) string { return o.Title },
+	},
+	"cart_id": {
+		Column: Column{Header: "CART ID"},
+		field:  "cart_id",
+		text:   func(o blink.Order) string { return o.CartID },
+	},
+}
+
+// OrderColumns lists the names accepted by --columns.
+var OrderColumns = []string{"date", "amount", "status", "items", "id", "location", "title", "cart_id"}
+
+// Default column sets for the table and the delimited formats.
+var (
+	DefaultTableColumns     = []string{"date", "amount", "id", "items"}
+	DefaultDelimitedColumns = []string{"date", "amount", "id", "status", "location", "items"}
+)
+
+// ParseColumns splits a comma-separated --columns value and checks each name.
+func ParseColumns(spec string) ([]string, error) {
+	var columns []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := orderColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q (known: %s)", name, strings.Join(OrderColumns, ", "))
+		}
+		columns = append(columns, name)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns given (known: %s)", strings.Join(OrderColumns, ", "))
+	}
+	return columns, nil
+}
+
+// OrdersOptions controls how order lists are rendered.
+type OrdersOptions struct {
+	// Columns selects and orders columns for the table, csv, tsv and
+	// markdown formats; nil means the format's defaults.
+	Columns []string
+	// Width is the terminal width the table is fitted to; 0 is unlimited.
+	Width int
+}
+
+// Orders bundles every rendering of an order list.
+func Orders(orders []blink.Order, opts OrdersOptions) Output {
+	records := make([]OrderRecord, 0, len(orders))
+	lines := make([]any, 0, len(orders))
+	for _, order := range orders {
+		record := NewOrderRecord(order)
+		records = append(records, record)
+		lines = append(lines, record)
+	}
+	return Output{
+		Text:    func() string { return OrdersTable(orders, opts) },
+		Value:   records,
+		Records: lines,
+		Rows:    func() Tabular { return OrdersRows(orders, opts.Columns) },
+	}
+}
+
+// OrdersRows lays orders out for csv, tsv and markdown. Items are joined
+// with "; " and dates use RFC 3339.
+func OrdersRows(orders []blink.Order, columns []string) Tabular {
+	if len(columns) == 0 {
+		columns = DefaultDelimitedColumns
+	}
+	var t Tabular
+	for _, name := range columns {
+		t.Header = append(t.Header, orderColumns[name].field)
+	}
 	for _, order := range orders {
-		date := ""
-		if !order.Date.IsZero() {
-			date = order.Date.Format("2006-01-02 15:04")
+		row := make([]string, len(columns))
+		for i, name := range columns {
+			c := orderColumns[name]
+			if c.raw != nil {
+				row[i] = c.raw(order)
+			} else {
+				row[i] = c.text(order)
+			}
 		}
-		amount := fmt.Sprintf("₹%d", order.AmountRupees)
-		items := strings.Join(order.Items, ", ")
-		if len(items) > 60 {
-			items = items[:57] + "..."
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// OrdersTable renders orders as aligned columns, wrapping items to fit
+// opts.Width.
+func OrdersTable(orders []blink.Order, opts OrdersOptions) string {
+	names := opts.Columns
+	if len(names) == 0 {
+		names = DefaultTableColumns
+	}
+	columns := make([]Column, len(names))
+	for i, name := range names {
+		columns[i] = orderColumns[name].Column
+	}
+	rows := make([][]string, 0, len(orders))
+	for _, order := range orders {
+		row := make([]string, len(names))
+		for i, name := range names {
+			row[i] = orderColumns[name].text(order)
 		}
-		lines = append(lines, fmt.Sprintf("%s | %s | %s | %s", date, amount, order.ID, items))
+		rows = append(rows, row)
 	}
-	return strings.Join(lines, "\n")
+	return RenderTable(columns, rows, opts.Width)
+}
+
+// SortKeys lists the fields accepted by SortOrders.
+var SortKeys = []string{"date", "amount", "status", "items", "id", "location"}
+
+// SortOrders orders the list by key, ascending, or descending with a
+// leading "-". Ties keep their current order.
+func SortOrders(orders []blink.Order, key string) error {
+	desc := strings.HasPrefix(key, "-")
+	key = strings.ToLower(strings.TrimPrefix(key, "-"))
+	var cmp func(a, b blink.Order) int
+	switch key {
+	case "date":
+		cmp = func(a, b blink.Order) int { return a.Date.Compare(b.Date) }
+	case "amount":
+		cmp = func(a, b blink.Order) int { return a.AmountRupees - b.AmountRupees }
+	case "status":
+		cmp = func(a, b blink.Order) int { return strings.Compare(a.Status, b.Status) }
+	case "items":
+		cmp = func(a, b blink.Order) int { return len(a.Items) - len(b.Items) }
+	case "id":
+		cmp = func(a, b blink.Order) int { return strings.Compare(a.ID, b.ID) }
+	case "location":
+		cmp = func(a, b blink.Order) int { return strings.Compare(a.Location, b.Location) }
+	default:
+		return fmt.Errorf("unknown sort key %q (known: %s, prefix - for descending)", key, strings.Join(SortKeys, ", "))
+	}
+	slices.SortStableFunc(orders, func(a, b blink.Order) int {
+		if desc {
+			return cmp(b, a)
+		}
+		return cmp(a, b)
+	})
+	return nil
 }
 
 // RelativeDate is a helper for CLI display when date is missing.
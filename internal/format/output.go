@@ -0,0 +1,119 @@
+package format
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Output formats accepted by --output.
+const (
+	Table    = "table"
+	JSON     = "json"
+	NDJSON   = "ndjson"
+	CSV      = "csv"
+	TSV      = "tsv"
+	Markdown = "markdown"
+)
+
+// Formats lists the output formats in the order shown to users.
+var Formats = []string{Table, JSON, NDJSON, CSV, TSV, Markdown}
+
+// Tabular is a header plus rows, used for the csv, tsv and markdown formats.
+type Tabular struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Output bundles the renderings of one result.
+type Output struct {
+	// Text renders the human-readable table format.
+	Text func() string
+	// Value is marshaled for json.
+	Value any
+	// Records are written one per line for ndjson.
+	Records []any
+	// Rows renders the csv, tsv and markdown formats.
+	Rows func() Tabular
+}
+
+// Write renders out in the named format.
+func Write(w io.Writer, format string, out Output) error {
+	switch format {
+	case Table:
+		_, err := fmt.Fprintln(w, out.Text())
+		return err
+	case JSON:
+		return WriteJSON(w, out.Value)
+	case NDJSON:
+		return WriteNDJSON(w, out.Records)
+	case CSV:
+		return WriteDelimited(w, out.Rows(), ',')
+	case TSV:
+		return WriteDelimited(w, out.Rows(), '\t')
+	case Markdown:
+		return WriteMarkdown(w, out.Rows())
+	}
+	return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteJSON writes v as indented JSON. Struct fields keep their declared
+// order and map keys are sorted, so equal values give identical output.
+func WriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// WriteNDJSON writes one compact JSON document per line.
+func WriteNDJSON(w io.Writer, records []any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDelimited writes t as CSV with the given separator.
+func WriteDelimited(w io.Writer, t Tabular, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(t.Header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// WriteMarkdown writes t as a GitHub-flavored Markdown table.
+func WriteMarkdown(w io.Writer, t Tabular) error {
+	lines := make([]string, 0, len(t.Rows)+2)
+	lines = append(lines, markdownRow(t.Header))
+	sep := make([]string, len(t.Header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	lines = append(lines, markdownRow(sep))
+	for _, row := range t.Rows {
+		lines = append(lines, markdownRow(row))
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		c = strings.ReplaceAll(c, "|", `\|`)
+		escaped[i] = strings.ReplaceAll(c, "\n", " ")
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
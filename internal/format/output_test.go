@@ -0,0 +1,78 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func testOrders() []blink.Order {
+	return []blink.Order{
+		{ID: "2", AmountRupees: 120, Status: "delivered", Date: time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC), Items: []string{"Milk", "Bread, brown"}},
+		{ID: "1", AmountRupees: 40},
+	}
+}
+
+func TestWriteOrdersJSON(t *testing.T) {
+	var first, second bytes.Buffer
+	if err := Write(&first, JSON, Orders(testOrders(), OrdersOptions{})); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := Write(&second, JSON, Orders(testOrders(), OrdersOptions{})); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Fatalf("json output is not deterministic")
+	}
+	out := first.String()
+	for _, want := range []string{`"date": "2024-03-02T09:30:00Z"`, `"date": null`, `"items": []`, `"location": ""`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteOrdersNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, NDJSON, Orders(testOrders(), OrdersOptions{})); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{"id":"2",`) {
+		t.Fatalf("unexpected ndjson:\n%s", buf.String())
+	}
+}
+
+func TestWriteDelimited(t *testing.T) {
+	var csvBuf, tsvBuf bytes.Buffer
+	if err := Write(&csvBuf, CSV, Orders(testOrders(), OrdersOptions{})); err != nil {
+		t.Fatalf("write csv: %v", err)
+	}
+	if err := Write(&tsvBuf, TSV, Orders(testOrders(), OrdersOptions{})); err != nil {
+		t.Fatalf("write tsv: %v", err)
+	}
+	wantCSV := "date,amount_rupees,id,status,location,items\n" +
+		"2024-03-02T09:30:00Z,120,2,delivered,,\"Milk; Bread, brown\"\n" +
+		",40,1,,,\n"
+	if csvBuf.String() != wantCSV {
+		t.Fatalf("unexpected csv:\n%s", csvBuf.String())
+	}
+	if !strings.HasPrefix(tsvBuf.String(), "date\tamount_rupees\tid\t") {
+		t.Fatalf("unexpected tsv:\n%s", tsvBuf.String())
+	}
+}
+
+func TestWriteMarkdownEscapes(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteMarkdown(&buf, Tabular{Header: []string{"a", "b"}, Rows: [][]string{{"x|y", "z"}}})
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want := "| a | b |\n| --- | --- |\n| x\\|y | z |\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected markdown:\n%s", buf.String())
+	}
+}
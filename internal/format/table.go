@@ -0,0 +1,103 @@
+package format
+
+import "strings"
+
+// Column describes one column of a rendered table.
+type Column struct {
+	Header string
+	// Right aligns cells to the right, for numbers.
+	Right bool
+	// Wrap lets the column shrink to fit the table width, wrapping its
+	// cells onto several lines. Other columns keep their natural width.
+	Wrap bool
+}
+
+// columnGap separates adjacent columns.
+const columnGap = "  "
+
+// minWrapWidth is the narrowest a wrapping column is squeezed to.
+const minWrapWidth = 10
+
+// RenderTable aligns rows under columns. With maxWidth > 0, wrapping columns
+// shrink, widest first, until the table fits; maxWidth 0 means unlimited.
+func RenderTable(columns []Column, rows [][]string, maxWidth int) string {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = DisplayWidth(c.Header)
+	}
+	for _, row := range rows {
+		for i := range columns {
+			if i < len(row) {
+				widths[i] = max(widths[i], DisplayWidth(row[i]))
+			}
+		}
+	}
+	if maxWidth > 0 {
+		fitWidths(columns, widths, maxWidth)
+	}
+
+	var b strings.Builder
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Header
+	}
+	writeRow(&b, columns, widths, header)
+	for _, row := range rows {
+		writeRow(&b, columns, widths, row)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// fitWidths narrows wrapping columns until the total fits in maxWidth.
+func fitWidths(columns []Column, widths []int, maxWidth int) {
+	total := DisplayWidth(columnGap) * (len(columns) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > maxWidth {
+		widest := -1
+		for i, c := range columns {
+			if !c.Wrap || widths[i] <= max(minWrapWidth, DisplayWidth(c.Header)) {
+				continue
+			}
+			if widest < 0 || widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+func writeRow(b *strings.Builder, columns []Column, widths []int, row []string) {
+	cells := make([][]string, len(columns))
+	height := 1
+	for i, c := range columns {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		switch {
+		case c.Wrap:
+			cells[i] = Wrap(cell, widths[i])
+		default:
+			cells[i] = []string{Truncate(cell, widths[i])}
+		}
+		height = max(height, len(cells[i]))
+	}
+	for line := 0; line < height; line++ {
+		parts := make([]string, len(columns))
+		for i, c := range columns {
+			text := ""
+			if line < len(cells[i]) {
+				text = cells[i][line]
+			}
+			parts[i] = pad(text, widths[i], c.Right)
+		}
+		b.WriteString(strings.TrimRight(strings.Join(parts, columnGap), " "))
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,100 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	cases := map[string]int{
+		"abc":  3,
+		"₹120": 4,
+		"दूध":  2, // combining vowel sign takes no cell
+		"牛乳":   4,
+		"é":   1,
+	}
+	for s, want := range cases {
+		if got := DisplayWidth(s); got != want {
+			t.Fatalf("DisplayWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	got := Truncate("₹₹₹₹₹", 3)
+	if got != "₹₹…" {
+		t.Fatalf("unexpected truncation %q", got)
+	}
+	if Truncate("दूध ब्रेड", 4) != "दूध …" {
+		t.Fatalf("unexpected truncation %q", Truncate("दूध ब्रेड", 4))
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := Wrap("Amul Taaza Milk, Bread, Eggs", 12)
+	want := []string{"Amul Taaza", "Milk, Bread,", "Eggs"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected wrap %q", got)
+	}
+	got = Wrap("abcdefghij", 4)
+	if strings.Join(got, "|") != "abcd|efgh|ij" {
+		t.Fatalf("unexpected hard wrap %q", got)
+	}
+	if got := Wrap("牛乳", 1); strings.Join(got, "|") != "牛|乳" {
+		t.Fatalf("unexpected narrow wrap %q", got)
+	}
+}
+
+func TestRenderTableFitsWidth(t *testing.T) {
+	columns := []Column{{Header: "ID"}, {Header: "AMOUNT", Right: true}, {Header: "ITEMS", Wrap: true}}
+	rows := [][]string{
+		{"1", "₹40", "Milk"},
+		{"22", "₹1200", "Amul Taaza Milk, Brown Bread, Farm Eggs"},
+	}
+	out := RenderTable(columns, rows, 30)
+	for _, line := range strings.Split(out, "\n") {
+		if DisplayWidth(line) > 30 {
+			t.Fatalf("line wider than 30: %q\n%s", line, out)
+		}
+	}
+	lines := strings.Split(out, "\n")
+	if lines[0] != "ID  AMOUNT  ITEMS" || lines[1] != "1      ₹40  Milk" {
+		t.Fatalf("unexpected table:\n%s", out)
+	}
+	if len(lines) < 4 {
+		t.Fatalf("expected items to wrap:\n%s", out)
+	}
+}
+
+func TestSortOrders(t *testing.T) {
+	orders := []blink.Order{
+		{ID: "a", AmountRupees: 50, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{ID: "b", AmountRupees: 200, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "c", AmountRupees: 120, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+	}
+	if err := SortOrders(orders, "-amount"); err != nil {
+		t.Fatalf("sort: %v", err)
+	}
+	if orders[0].ID != "b" || orders[2].ID != "a" {
+		t.Fatalf("unexpected order %v", orders)
+	}
+	if err := SortOrders(orders, "date"); err != nil || orders[0].ID != "b" {
+		t.Fatalf("unexpected date sort %v (%v)", orders, err)
+	}
+	if err := SortOrders(orders, "bogus"); err == nil {
+		t.Fatalf("expected an error for an unknown key")
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	got, err := ParseColumns("date, Amount,id")
+	if err != nil || strings.Join(got, ",") != "date,amount,id" {
+		t.Fatalf("unexpected columns %v (%v)", got, err)
+	}
+	if _, err := ParseColumns("date,price"); err == nil {
+		t.Fatalf("expected an error for an unknown column")
+	}
+}
@@ -0,0 +1,83 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateFuncs are the helpers available to --template. Arguments come
+// first so they read well in pipelines:
+//
+//	{{.AmountRupees | rupees}}     ₹1,23,456
+//	{{.Date | date "02 Jan 2006"}} 02 Mar 2024
+//	{{.Items | join ", "}}
+//	{{.Title | truncate 20}}
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"rupees":   Rupees,
+		"date":     Date,
+		"join":     Join,
+		"truncate": truncateArg,
+	}
+}
+
+// ParseTemplate parses text with TemplateFuncs available.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Option("missingkey=error").Parse(text)
+}
+
+// ExecuteTemplate runs t on data and ends the output with a newline.
+func ExecuteTemplate(w io.Writer, t *template.Template, data any) error {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return err
+	}
+	out := b.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// Rupees formats a whole-rupee amount with Indian digit grouping, e.g.
+// ₹12,34,567.
+func Rupees(amount int) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := fmt.Sprint(amount)
+	if len(digits) <= 3 {
+		return sign + "₹" + digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > 2 {
+		groups = append([]string{head[len(head)-2:]}, groups...)
+		head = head[:len(head)-2]
+	}
+	groups = append([]string{head}, groups...)
+	return sign + "₹" + strings.Join(groups, ",") + "," + tail
+}
+
+// Date formats t with a Go layout, or returns "" for the zero time.
+func Date(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// Join joins items with sep.
+func Join(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// truncateArg is Truncate with the width first, for pipelines.
+func truncateArg(width int, s string) string {
+	return Truncate(s, width)
+}
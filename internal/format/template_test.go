@@ -0,0 +1,45 @@
+package format
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestRupees(t *testing.T) {
+	cases := map[int]string{
+		0:          "₹0",
+		999:        "₹999",
+		1000:       "₹1,000",
+		123456:     "₹1,23,456",
+		1234567:    "₹12,34,567",
+		1234567890: "₹1,23,45,67,890",
+		-54321:     "-₹54,321",
+	}
+	for amount, want := range cases {
+		if got := Rupees(amount); got != want {
+			t.Fatalf("Rupees(%d) = %q, want %q", amount, got, want)
+		}
+	}
+}
+
+func TestTemplateHelpers(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `{{.Date | date "02 Jan 2006"}} {{.AmountRupees | rupees}} {{.Items | join ", " | truncate 12}}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	order := blink.Order{
+		AmountRupees: 12500,
+		Date:         time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC),
+		Items:        []string{"Milk", "Bread", "Eggs"},
+	}
+	var buf bytes.Buffer
+	if err := ExecuteTemplate(&buf, tmpl, order); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if want := "02 Mar 2024 ₹12,500 Milk, Bread…\n"; buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+}
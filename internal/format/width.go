@@ -0,0 +1,143 @@
+package format
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DisplayWidth returns the number of terminal cells s occupies: combining
+// marks and format characters take none, East Asian wide characters and
+// emoji take two.
+func DisplayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// isWide reports East Asian wide and fullwidth characters and emoji.
+func isWide(r rune) bool {
+	return r >= 0x1100 && (r <= 0x115f || // Hangul Jamo
+		r == 0x2329 || r == 0x232a ||
+		(r >= 0x2e80 && r <= 0xa4cf && r != 0x303f) || // CJK .. Yi
+		(r >= 0xac00 && r <= 0xd7a3) || // Hangul syllables
+		(r >= 0xf900 && r <= 0xfaff) || // CJK compatibility ideographs
+		(r >= 0xfe30 && r <= 0xfe4f) || // CJK compatibility forms
+		(r >= 0xff00 && r <= 0xff60) || // fullwidth forms
+		(r >= 0xffe0 && r <= 0xffe6) ||
+		(r >= 0x1f300 && r <= 0x1f64f) || // pictographs, emoticons
+		(r >= 0x1f900 && r <= 0x1f9ff) ||
+		(r >= 0x20000 && r <= 0x3fffd))
+}
+
+// Truncate shortens s to at most width cells, marking the cut with "…".
+// It never splits a character.
+func Truncate(s string, width int) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	b.WriteString("…")
+	return b.String()
+}
+
+// Wrap breaks s into lines of at most width cells, at spaces where
+// possible. Words longer than width are split.
+func Wrap(s string, width int) []string {
+	if width <= 0 || DisplayWidth(s) <= width {
+		return []string{s}
+	}
+	var (
+		lines []string
+		line  strings.Builder
+		used  int
+	)
+	flush := func() {
+		lines = append(lines, strings.TrimRight(line.String(), " "))
+		line.Reset()
+		used = 0
+	}
+	for _, word := range strings.Fields(s) {
+		w := DisplayWidth(word)
+		if used > 0 && used+1+w > width {
+			flush()
+		}
+		if used > 0 {
+			line.WriteByte(' ')
+			used++
+		}
+		for w > width-used {
+			// Split a word that cannot fit on a line of its own.
+			head, rest := splitAt(word, width-used)
+			if head == "" {
+				if used > 0 {
+					flush()
+					continue
+				}
+				// Not even one character fits; emit it anyway.
+				_, size := utf8.DecodeRuneInString(word)
+				head, rest = word[:size], word[size:]
+			}
+			line.WriteString(head)
+			flush()
+			word, w = rest, DisplayWidth(rest)
+		}
+		line.WriteString(word)
+		used += w
+	}
+	if used > 0 || len(lines) == 0 {
+		flush()
+	}
+	return lines
+}
+
+// splitAt returns the longest prefix of s that fits in width cells and the rest.
+func splitAt(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := runeWidth(r)
+		if used+w > width {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
+
+// pad fills s with spaces to width cells, on the left when right is set.
+func pad(s string, width int, right bool) string {
+	n := width - DisplayWidth(s)
+	if n <= 0 {
+		return s
+	}
+	if right {
+		return strings.Repeat(" ", n) + s
+	}
+	return s + strings.Repeat(" ", n)
+}
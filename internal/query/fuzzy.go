@@ -0,0 +1,159 @@
+package query
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"blinkcli/internal/blink"
+)
+
+// Match is a fuzzy match of a query within one item name.
+type Match struct {
+	Item  string
+	Score int
+	// Positions are the indexes of the matched runes in Item.
+	Positions []int
+}
+
+// Result is an order with the items that matched a search.
+type Result struct {
+	Order   blink.Order
+	Matches []Match
+	// Score is the best item score.
+	Score int
+}
+
+// FuzzyMatch matches query against item case-insensitively. Every word of
+// the query must appear in item, either as a substring or as its letters in
+// order. Substrings, word starts and consecutive letters score higher.
+func FuzzyMatch(query, item string) (Match, bool) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return Match{}, false
+	}
+	runes := []rune(item)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	m := Match{Item: item}
+	used := map[int]bool{}
+	for _, word := range words {
+		positions, score, ok := matchWord([]rune(word), lower)
+		if !ok {
+			return Match{}, false
+		}
+		m.Score += score
+		for _, p := range positions {
+			if !used[p] {
+				used[p] = true
+				m.Positions = append(m.Positions, p)
+			}
+		}
+	}
+	sort.Ints(m.Positions)
+	return m, true
+}
+
+// matchWord finds word in text, preferring a substring at a word start,
+// then any substring, then a scattered subsequence.
+func matchWord(word, text []rune) ([]int, int, bool) {
+	best := -1
+	for i := 0; i+len(word) <= len(text); i++ {
+		if string(text[i:i+len(word)]) != string(word) {
+			continue
+		}
+		if best < 0 || (isWordStart(text, i) && !isWordStart(text, best)) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		positions := make([]int, len(word))
+		for i := range word {
+			positions[i] = best + i
+		}
+		score := 10 * len(word)
+		if isWordStart(text, best) {
+			score += 10
+		}
+		return positions, score, true
+	}
+
+	positions := make([]int, 0, len(word))
+	score := 0
+	j := 0
+	for i := 0; i < len(text) && j < len(word); i++ {
+		if text[i] != word[j] {
+			continue
+		}
+		score++
+		if isWordStart(text, i) {
+			score += 3
+		}
+		if len(positions) > 0 && positions[len(positions)-1] == i-1 {
+			score += 2
+		}
+		positions = append(positions, i)
+		j++
+	}
+	if j < len(word) {
+		return nil, 0, false
+	}
+	return positions, score, true
+}
+
+func isWordStart(text []rune, i int) bool {
+	return i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1])
+}
+
+// Search returns the orders with at least one item matching query, best
+// matches first and newest first among equals.
+func Search(orders []blink.Order, query string) []Result {
+	var results []Result
+	for _, order := range orders {
+		r := Result{Order: order}
+		for _, item := range order.Items {
+			if m, ok := FuzzyMatch(query, item); ok {
+				r.Matches = append(r.Matches, m)
+				r.Score = max(r.Score, m.Score)
+			}
+		}
+		if len(r.Matches) > 0 {
+			results = append(results, r)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Order.Date.After(results[j].Order.Date)
+	})
+	return results
+}
+
+// Highlight wraps each run of matched runes in open and close.
+func Highlight(m Match, open, close string) string {
+	matched := map[int]bool{}
+	for _, p := range m.Positions {
+		matched[p] = true
+	}
+	var b strings.Builder
+	in := false
+	for i, r := range []rune(m.Item) {
+		if matched[i] != in {
+			if in {
+				b.WriteString(close)
+			} else {
+				b.WriteString(open)
+			}
+			in = !in
+		}
+		b.WriteRune(r)
+	}
+	if in {
+		b.WriteString(close)
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+// Package query filters and searches stored orders.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+// Filter narrows an order list. Zero fields don't filter.
+type Filter struct {
+	// Since is inclusive, Until exclusive.
+	Since, Until time.Time
+	// Min and Max bound the amount in rupees; Max 0 means no upper bound.
+	Min, Max int
+	// Status matches case-insensitively.
+	Status string
+	// Item must match at least one item name.
+	Item *regexp.Regexp
+	// Location matches the saved location the order was synced under,
+	// case-insensitively.
+	Location string
+}
+
+// Apply returns the orders matching every set field, in their original order.
+// Orders without a date are dropped when Since or Until is set.
+func (f Filter) Apply(orders []blink.Order) []blink.Order {
+	filtered := make([]blink.Order, 0, len(orders))
+	for _, order := range orders {
+		if f.Match(order) {
+			filtered = append(filtered, order)
+		}
+	}
+	return filtered
+}
+
+// Match reports whether one order passes the filter.
+func (f Filter) Match(order blink.Order) bool {
+	if !f.Since.IsZero() && (order.Date.IsZero() || order.Date.Before(f.Since)) {
+		return false
+	}
+	if !f.Until.IsZero() && (order.Date.IsZero() || !order.Date.Before(f.Until)) {
+		return false
+	}
+	if order.AmountRupees < f.Min || (f.Max > 0 && order.AmountRupees > f.Max) {
+		return false
+	}
+	if f.Status != "" && !strings.EqualFold(order.Status, f.Status) {
+		return false
+	}
+	if f.Location != "" && !strings.EqualFold(order.Location, f.Location) {
+		return false
+	}
+	if f.Item != nil {
+		for _, item := range order.Items {
+			if f.Item.MatchString(item) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// ItemPattern compiles an --item value: a case-insensitive regular
+// expression, or a plain substring when it isn't valid regex syntax.
+func ItemPattern(pattern string) *regexp.Regexp {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(pattern))
+	}
+	return re
+}
+
+// Time formats accepted by ParseTime besides relative durations.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// ParseTime reads an absolute date (2024-03-01, 2024-03, 2024, RFC 3339) in
+// loc, or a duration before now: 12h, 30d, 2w, 6m (months) or 1y.
+//
+// With end set, a date without a time names its whole period: "2024-03"
+// resolves to the start of April, so it can be used as an exclusive bound.
+func ParseTime(value string, now time.Time, loc *time.Location, end bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, ok, err := parseRelative(value, now); ok {
+		return t, err
+	}
+	for _, layout := range timeLayouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			continue
+		}
+		if end {
+			switch layout {
+			case "2006-01-02":
+				t = t.AddDate(0, 0, 1)
+			case "2006-01":
+				t = t.AddDate(0, 1, 0)
+			case "2006":
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, YYYY-MM, YYYY or a duration like 30d, 2w, 6m, 1y)", value)
+}
+
+var relativePattern = regexp.MustCompile(`^(\d+)([hdwmy])$`)
+
+func parseRelative(value string, now time.Time) (time.Time, bool, error) {
+	m := relativePattern.FindStringSubmatch(strings.ToLower(value))
+	if m == nil {
+		return time.Time{}, false, nil
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("invalid duration %q", value)
+	}
+	switch m[2] {
+	case "h":
+		return now.Add(-time.Duration(n) * time.Hour), true, nil
+	case "d":
+		return now.AddDate(0, 0, -n), true, nil
+	case "w":
+		return now.AddDate(0, 0, -7*n), true, nil
+	case "m":
+		return now.AddDate(0, -n, 0), true, nil
+	}
+	return now.AddDate(-n, 0, 0), true, nil
+}
+
+// Statuses lists the distinct order statuses, in first-seen order.
+func Statuses(orders []blink.Order) []string {
+	seen := map[string]bool{}
+	var statuses []string
+	for _, order := range orders {
+		if order.Status != "" && !seen[order.Status] {
+			seen[order.Status] = true
+			statuses = append(statuses, order.Status)
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func ids(orders []blink.Order) string {
+	var out []string
+	for _, o := range orders {
+		out = append(out, o.ID)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestFilterApply(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	orders := []blink.Order{
+		{ID: "1", Date: day(1), AmountRupees: 100, Status: "Delivered", Items: []string{"Amul Milk"}, Location: "home"},
+		{ID: "2", Date: day(10), AmountRupees: 600, Status: "Cancelled", Items: []string{"Eggs"}},
+		{ID: "3", Date: day(20), AmountRupees: 300, Status: "Delivered", Items: []string{"Curd", "Bread"}, Location: "Office"},
+		{ID: "4", AmountRupees: 50},
+	}
+	cases := []struct {
+		filter Filter
+		want   string
+	}{
+		{Filter{}, "1,2,3,4"},
+		{Filter{Since: day(5)}, "2,3"},
+		{Filter{Until: day(10)}, "1"},
+		{Filter{Min: 200, Max: 500}, "3"},
+		{Filter{Status: "delivered"}, "1,3"},
+		{Filter{Item: ItemPattern("milk|curd")}, "1,3"},
+		{Filter{Item: ItemPattern("(")}, ""},
+		{Filter{Location: "office"}, "3"},
+	}
+	for _, tc := range cases {
+		if got := ids(tc.filter.Apply(orders)); got != tc.want {
+			t.Fatalf("%+v: got %q, want %q", tc.filter, got, tc.want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 3, 31, 18, 0, 0, 0, time.UTC)
+	cases := []struct {
+		value string
+		end   bool
+		want  time.Time
+	}{
+		{"30d", false, time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{"2w", false, time.Date(2024, 3, 17, 18, 0, 0, 0, time.UTC)},
+		{"1y", false, time.Date(2023, 3, 31, 18, 0, 0, 0, time.UTC)},
+		{"2024-03-05", false, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-05", true, time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"2024-02", true, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-02-10T08:30", true, time.Date(2024, 2, 10, 8, 30, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		got, err := ParseTime(tc.value, now, time.UTC, tc.end)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.value, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("%s (end=%v): got %v, want %v", tc.value, tc.end, got, tc.want)
+		}
+	}
+	if _, err := ParseTime("last week", now, time.UTC, false); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	m, ok := FuzzyMatch("MILK", "Amul Taaza Milk")
+	if !ok || Highlight(m, "[", "]") != "Amul Taaza [Milk]" {
+		t.Fatalf("unexpected substring match %+v", m)
+	}
+	m, ok = FuzzyMatch("amltz", "Amul Taaza Milk")
+	if !ok || Highlight(m, "[", "]") != "[Am]u[l] [T]aa[z]a Milk" {
+		t.Fatalf("unexpected fuzzy match %q", Highlight(m, "[", "]"))
+	}
+	m, ok = FuzzyMatch("bread amul", "Amul Brown Bread")
+	if !ok || Highlight(m, "[", "]") != "[Amul] Brown [Bread]" {
+		t.Fatalf("unexpected multi-word match %q", Highlight(m, "[", "]"))
+	}
+	if _, ok := FuzzyMatch("milk", "Mother Dairy Curd"); ok {
+		t.Fatalf("expected no match")
+	}
+}
+
+func TestSearchRanksSubstringsFirst(t *testing.T) {
+	orders := []blink.Order{
+		{ID: "fuzzy", Items: []string{"Malai Kulfi"}},
+		{ID: "exact", Items: []string{"Cow Milk"}},
+	}
+	results := Search(orders, "milk")
+	if len(results) != 1 || results[0].Order.ID != "exact" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	results = Search(orders, "mlk")
+	if len(results) != 2 {
+		t.Fatalf("expected both orders to match, got %+v", results)
+	}
+}
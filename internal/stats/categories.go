@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/category"
+)
+
+// CategoryBuckets breaks orders down by item category. Count is the orders
+// with an item in the category and Amount an estimate: each order's total
+// split evenly across its items. Items categorize returns "" for are
+// counted as category.Uncategorized. Buckets are sorted by amount, largest
+// first.
+func CategoryBuckets(orders []blink.Order, categorize func(item string) string) []Bucket {
+	amounts := map[string]float64{}
+	counts := map[string]int{}
+	for _, order := range orders {
+		if len(order.Items) == 0 {
+			continue
+		}
+		share := float64(order.AmountRupees) / float64(len(order.Items))
+		seen := map[string]bool{}
+		for _, item := range order.Items {
+			name := categorize(item)
+			if name == "" {
+				name = category.Uncategorized
+			}
+			amounts[name] += share
+			if !seen[name] {
+				seen[name] = true
+				counts[name]++
+			}
+		}
+	}
+	buckets := make([]Bucket, 0, len(amounts))
+	for name, amount := range amounts {
+		buckets = append(buckets, Bucket{Label: name, Count: counts[name], Amount: int(math.Round(amount))})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Amount != buckets[j].Amount {
+			return buckets[i].Amount > buckets[j].Amount
+		}
+		return buckets[i].Label < buckets[j].Label
+	})
+	return buckets
+}
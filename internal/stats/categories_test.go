@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+
+	"blinkcli/internal/blink"
+)
+
+func TestCategoryBuckets(t *testing.T) {
+	orders := []blink.Order{
+		{AmountRupees: 300, Items: []string{"Milk", "Curd", "Chips"}},
+		{AmountRupees: 500, Items: []string{"Gadget"}},
+		{AmountRupees: 90, Items: []string{"Milk"}},
+		{AmountRupees: 100},
+	}
+	categorize := func(item string) string {
+		switch strings.ToLower(item) {
+		case "milk", "curd":
+			return "dairy"
+		case "chips":
+			return "snacks"
+		}
+		return ""
+	}
+	buckets := CategoryBuckets(orders, categorize)
+	want := []Bucket{
+		{Label: "uncategorized", Count: 1, Amount: 500},
+		{Label: "dairy", Count: 2, Amount: 290},
+		{Label: "snacks", Count: 1, Amount: 100},
+	}
+	if len(buckets) != len(want) {
+		t.Fatalf("expected %v, got %v", want, buckets)
+	}
+	for i := range want {
+		if buckets[i] != want[i] {
+			t.Fatalf("bucket %d: expected %+v, got %+v", i, want[i], buckets[i])
+		}
+	}
+}
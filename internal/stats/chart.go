@@ -0,0 +1,228 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/format"
+)
+
+// Metrics charted by 'stats --chart'.
+const (
+	MetricAmount = "amount"
+	MetricCount  = "count"
+	MetricAvg    = "avg"
+)
+
+// Metrics lists the values accepted by 'stats --metric'.
+var Metrics = []string{MetricAmount, MetricCount, MetricAvg}
+
+// defaultChartWidth is used when the output is not a terminal.
+const defaultChartWidth = 80
+
+// Sparkline ticks, lowest first. Months without orders are left blank.
+var (
+	UnicodeTicks = []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	ASCIITicks   = []string{"_", ".", "-", "~", "=", "+", "*", "#"}
+)
+
+// eighths are the partial blocks that end a Unicode bar, one eighth first.
+var eighths = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// metricValue is the bucket's amount, order count or average order value.
+func metricValue(b Bucket, metric string) float64 {
+	switch metric {
+	case MetricCount:
+		return float64(b.Count)
+	case MetricAvg:
+		if b.Count == 0 {
+			return 0
+		}
+		return float64(b.Amount) / float64(b.Count)
+	}
+	return float64(b.Amount)
+}
+
+func metricLabel(v float64, metric string) string {
+	if metric == MetricCount {
+		return strconv.Itoa(int(v))
+	}
+	return format.Rupees(int(math.Round(v)))
+}
+
+func metricTitle(metric string) string {
+	switch metric {
+	case MetricCount:
+		return "orders"
+	case MetricAvg:
+		return "average order"
+	}
+	return "spend"
+}
+
+// Sparkline draws one tick per value, scaled so peak gets the top tick.
+// Zero values are blank and any other value gets at least the lowest tick.
+func Sparkline(values []float64, peak float64, ticks []string) string {
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || peak <= 0 {
+			b.WriteString(" ")
+			continue
+		}
+		level := int(math.Ceil(v/peak*float64(len(ticks)))) - 1
+		b.WriteString(ticks[min(max(level, 0), len(ticks)-1)])
+	}
+	return b.String()
+}
+
+// bar draws v/peak of cells columns, in eighths with Unicode. Any value
+// above zero gets at least the smallest mark.
+func bar(v, peak float64, cells int, unicode bool) string {
+	if v <= 0 || peak <= 0 {
+		return ""
+	}
+	if !unicode {
+		return strings.Repeat("#", max(1, int(math.Round(v/peak*float64(cells)))))
+	}
+	n := max(1, int(math.Round(v/peak*float64(cells*8))))
+	s := strings.Repeat("█", n/8)
+	if n%8 > 0 {
+		s += eighths[n%8-1]
+	}
+	return s
+}
+
+// fillMonths returns a bucket for every month from the first of monthly to
+// the last, with empty buckets for the months without orders.
+func fillMonths(monthly []Bucket) []Bucket {
+	if len(monthly) == 0 {
+		return nil
+	}
+	have := map[string]Bucket{}
+	for _, b := range monthly {
+		have[b.Label] = b
+	}
+	first, err1 := time.Parse("2006-01", monthly[0].Label)
+	last, err2 := time.Parse("2006-01", monthly[len(monthly)-1].Label)
+	if err1 != nil || err2 != nil {
+		return monthly
+	}
+	var filled []Bucket
+	for m := first; !m.After(last); m = m.AddDate(0, 1, 0) {
+		label := m.Format("2006-01")
+		b, ok := have[label]
+		if !ok {
+			b = Bucket{Label: label}
+		}
+		filled = append(filled, b)
+	}
+	return filled
+}
+
+// monthlyPeak is the largest monthly value of metric.
+func monthlyPeak(monthly []Bucket, metric string) float64 {
+	peak := 0.0
+	for _, b := range monthly {
+		peak = max(peak, metricValue(b, metric))
+	}
+	return peak
+}
+
+// yearSparkline draws a year's months, January to December, scaled to peak.
+func yearSparkline(year string, monthly []Bucket, metric string, peak float64, ticks []string) string {
+	values := make([]float64, 12)
+	for _, b := range monthly {
+		if y, m, ok := strings.Cut(b.Label, "-"); ok && y == year {
+			if i, err := strconv.Atoi(m); err == nil && i >= 1 && i <= 12 {
+				values[i-1] = metricValue(b, metric)
+			}
+		}
+	}
+	return Sparkline(values, peak, ticks)
+}
+
+// formatYearly renders the yearly section with a sparkline of each year's
+// months after its totals, all on one scale.
+func formatYearly(summary Summary, opts SummaryOptions) []string {
+	ticks := ASCIITicks
+	if opts.Unicode {
+		ticks = UnicodeTicks
+	}
+	peak := monthlyPeak(summary.Monthly, opts.Metric)
+	totals := make([]string, len(summary.Yearly))
+	width := 0
+	for i, b := range summary.Yearly {
+		totals[i] = fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount)
+		width = max(width, format.DisplayWidth(totals[i]))
+	}
+	lines := []string{fmt.Sprintf("Yearly (%s by month, Jan to Dec):", metricTitle(opts.Metric))}
+	for i, b := range summary.Yearly {
+		pad := strings.Repeat(" ", width-format.DisplayWidth(totals[i]))
+		spark := yearSparkline(b.Label, summary.Monthly, opts.Metric, peak, ticks)
+		lines = append(lines, strings.TrimRight(totals[i]+pad+"  "+spark, " "))
+	}
+	return lines
+}
+
+// formatChart renders the monthly section as sparklines of spend, orders
+// and average order, then a bar per month of opts.Metric, fitted to
+// opts.Width.
+func formatChart(summary Summary, opts SummaryOptions) []string {
+	width := opts.Width
+	if width <= 0 {
+		width = defaultChartWidth
+	}
+	ticks := ASCIITicks
+	if opts.Unicode {
+		ticks = UnicodeTicks
+	}
+	months := fillMonths(summary.Monthly)
+	if len(months) == 0 {
+		return nil
+	}
+
+	trends := []struct {
+		name   string
+		metric string
+	}{
+		{"Spend", MetricAmount},
+		{"Orders", MetricCount},
+		{"Average", MetricAvg},
+	}
+	suffixes := make([]string, len(trends))
+	suffixWidth := 0
+	for i, t := range trends {
+		suffixes[i] = "peak " + metricLabel(monthlyPeak(months, t.metric), t.metric)
+		suffixWidth = max(suffixWidth, format.DisplayWidth(suffixes[i]))
+	}
+	// "  Average  " before the sparkline and two spaces after it.
+	shown := months[max(0, len(months)-max(12, width-11-2-suffixWidth)):]
+	lines := []string{fmt.Sprintf("Monthly trend, %s to %s:", shown[0].Label, shown[len(shown)-1].Label)}
+	for i, t := range trends {
+		values := make([]float64, len(shown))
+		for j, b := range shown {
+			values[j] = metricValue(b, t.metric)
+		}
+		spark := Sparkline(values, monthlyPeak(months, t.metric), ticks)
+		lines = append(lines, fmt.Sprintf("  %-7s  %s  %s", t.name, spark, suffixes[i]))
+	}
+
+	peak := monthlyPeak(months, opts.Metric)
+	values := make([]string, len(months))
+	valueWidth := 0
+	for i, b := range months {
+		values[i] = metricLabel(metricValue(b, opts.Metric), opts.Metric)
+		valueWidth = max(valueWidth, format.DisplayWidth(values[i]))
+	}
+	// "  2024-03  " before the bar and a space before the value.
+	cells := max(10, width-11-1-valueWidth)
+	lines = append(lines, fmt.Sprintf("Monthly %s:", metricTitle(opts.Metric)))
+	for i, b := range months {
+		drawn := bar(metricValue(b, opts.Metric), peak, cells, opts.Unicode)
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("  %s  %s %s", b.Label, drawn, values[i]), " "))
+	}
+	return lines
+}
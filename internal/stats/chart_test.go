@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+func TestSparkline(t *testing.T) {
+	got := Sparkline([]float64{0, 1, 4, 8, 5}, 8, UnicodeTicks)
+	if got != " ▁▄█▅" {
+		t.Fatalf("unexpected sparkline %q", got)
+	}
+	if got := Sparkline([]float64{0, 0}, 0, ASCIITicks); got != "  " {
+		t.Fatalf("expected blanks without a peak, got %q", got)
+	}
+}
+
+func TestBar(t *testing.T) {
+	if got := bar(3, 4, 2, true); got != "█▌" {
+		t.Fatalf("unexpected unicode bar %q", got)
+	}
+	if got := bar(1, 1000, 10, false); got != "#" {
+		t.Fatalf("expected the smallest mark, got %q", got)
+	}
+	if got := bar(0, 10, 10, true); got != "" {
+		t.Fatalf("expected no bar for zero, got %q", got)
+	}
+}
+
+func TestFormatSummaryChart(t *testing.T) {
+	orders := []blink.Order{
+		{Date: time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC), AmountRupees: 400},
+		{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), AmountRupees: 1000},
+		{Date: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), AmountRupees: 600},
+		{Date: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), AmountRupees: 800},
+	}
+	summary := BuildSummary(orders)
+	text := FormatSummary(summary, SummaryOptions{Chart: true, Metric: MetricCount, Width: 50, Unicode: true})
+	lines := strings.Split(text, "\n")
+	for _, want := range []string{
+		"  2023: 1 orders, ₹400" + strings.Repeat(" ", 13) + "▄",
+		"  2024: 3 orders, ₹2400  █ ▄",
+		"Monthly trend, 2023-11 to 2024-03:",
+		"  Orders   ▄ █ ▄  peak 2",
+		"Monthly orders:",
+		"  2023-12   0",
+	} {
+		found := false
+		for _, line := range lines {
+			if strings.TrimSuffix(line, " ") == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("missing line %q in:\n%s", want, text)
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "  20") && format.DisplayWidth(line) > 50 {
+			t.Errorf("line wider than 50 columns: %q", line)
+		}
+	}
+	if strings.Contains(text, "Monthly:") {
+		t.Errorf("expected the monthly list to be replaced:\n%s", text)
+	}
+}
@@ -0,0 +1,308 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// Period lengths accepted by Compare.
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+	PeriodYear    = "year"
+)
+
+// Periods lists the values accepted by 'stats compare --period'.
+var Periods = []string{PeriodMonth, PeriodQuarter, PeriodYear}
+
+// PeriodStats summarizes the orders placed in [Start, End).
+type PeriodStats struct {
+	// Label is 2024-04, 2024-Q2 or 2024.
+	Label  string    `json:"label"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Orders int       `json:"orders"`
+	Amount int       `json:"amount_rupees"`
+	// AvgBasket is the mean order value, 0 without orders.
+	AvgBasket int         `json:"avg_basket_rupees"`
+	TopItems  []ItemCount `json:"top_items"`
+}
+
+// ItemCount is a product and the number of orders containing it.
+type ItemCount struct {
+	Name   string `json:"name"`
+	Orders int    `json:"orders"`
+}
+
+// Change is the difference from a base value. Percent is nil when the base
+// is 0.
+type Change struct {
+	Absolute int      `json:"absolute"`
+	Percent  *float64 `json:"percent"`
+}
+
+// Delta compares the metrics of two periods.
+type Delta struct {
+	Orders    Change `json:"orders"`
+	Amount    Change `json:"amount_rupees"`
+	AvgBasket Change `json:"avg_basket_rupees"`
+}
+
+// Comparison is the json output of 'stats compare'. For years the previous
+// period is the same period last year, so LastYear and VsLastYear are nil.
+type Comparison struct {
+	Period     string       `json:"period"`
+	ToDate     bool         `json:"to_date"`
+	Current    PeriodStats  `json:"current"`
+	Previous   PeriodStats  `json:"previous"`
+	LastYear   *PeriodStats `json:"last_year"`
+	VsPrevious Delta        `json:"vs_previous"`
+	VsLastYear *Delta       `json:"vs_last_year"`
+}
+
+// ComparisonRecord is one ndjson line of 'stats compare'. Role is
+// "current", "previous" or "last_year".
+type ComparisonRecord struct {
+	Role string `json:"role"`
+	PeriodStats
+}
+
+// periodStart returns the start of the period containing t.
+func periodStart(period string, t time.Time) time.Time {
+	switch period {
+	case PeriodQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
+	case PeriodYear:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// periodMonths is the length of a period in months.
+func periodMonths(period string) int {
+	switch period {
+	case PeriodQuarter:
+		return 3
+	case PeriodYear:
+		return 12
+	}
+	return 1
+}
+
+func periodLabel(period string, start time.Time) string {
+	switch period {
+	case PeriodQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())+2)/3)
+	case PeriodYear:
+		return start.Format("2006")
+	}
+	return start.Format("2006-01")
+}
+
+// Compare summarizes the period containing now, the one before it and, for
+// months and quarters, the same period a year earlier. The current period
+// only has orders up to now; with toDate the earlier periods are cut at the
+// same point, for a like-for-like comparison. Each period lists its top
+// products by orders.
+func Compare(orders []blink.Order, now time.Time, period string, toDate bool, top int) (Comparison, error) {
+	if !slices.Contains(Periods, period) {
+		return Comparison{}, fmt.Errorf("unknown period %q (known: %s)", period, strings.Join(Periods, ", "))
+	}
+	months := periodMonths(period)
+	start := periodStart(period, now)
+	elapsed := now.Sub(start)
+	window := func(start time.Time) PeriodStats {
+		end := start.AddDate(0, months, 0)
+		if toDate && start.Add(elapsed).Before(end) {
+			end = start.Add(elapsed)
+		}
+		return periodStats(orders, period, start, end, top)
+	}
+
+	c := Comparison{
+		Period:   period,
+		ToDate:   toDate,
+		Current:  window(start),
+		Previous: window(start.AddDate(0, -months, 0)),
+	}
+	c.VsPrevious = delta(c.Current, c.Previous)
+	if period != PeriodYear {
+		lastYear := window(start.AddDate(-1, 0, 0))
+		vs := delta(c.Current, lastYear)
+		c.LastYear, c.VsLastYear = &lastYear, &vs
+	}
+	return c, nil
+}
+
+func periodStats(orders []blink.Order, period string, start, end time.Time, top int) PeriodStats {
+	s := PeriodStats{Label: periodLabel(period, start), Start: start, End: end, TopItems: []ItemCount{}}
+	var in []blink.Order
+	for _, order := range orders {
+		if order.Date.Before(start) || !order.Date.Before(end) {
+			continue
+		}
+		in = append(in, order)
+		s.Orders++
+		s.Amount += order.AmountRupees
+	}
+	if s.Orders > 0 {
+		s.AvgBasket = int(math.Round(float64(s.Amount) / float64(s.Orders)))
+	}
+	if top > 0 {
+		for _, item := range ItemStats(in, end, ByOrders, top) {
+			s.TopItems = append(s.TopItems, ItemCount{Name: item.Name, Orders: item.Orders})
+		}
+	}
+	return s
+}
+
+func delta(current, base PeriodStats) Delta {
+	return Delta{
+		Orders:    change(current.Orders, base.Orders),
+		Amount:    change(current.Amount, base.Amount),
+		AvgBasket: change(current.AvgBasket, base.AvgBasket),
+	}
+}
+
+func change(value, base int) Change {
+	c := Change{Absolute: value - base}
+	if base != 0 {
+		pct := math.Round(float64(value-base)/float64(base)*1000) / 10
+		c.Percent = &pct
+	}
+	return c
+}
+
+// ComparisonOutput bundles every rendering of a comparison.
+func ComparisonOutput(c Comparison) format.Output {
+	records := []any{
+		ComparisonRecord{Role: "current", PeriodStats: c.Current},
+		ComparisonRecord{Role: "previous", PeriodStats: c.Previous},
+	}
+	if c.LastYear != nil {
+		records = append(records, ComparisonRecord{Role: "last_year", PeriodStats: *c.LastYear})
+	}
+	return format.Output{
+		Text:    func() string { return FormatComparison(c) },
+		Value:   c,
+		Records: records,
+		Rows:    func() format.Tabular { return comparisonRows(c) },
+	}
+}
+
+// comparisonMetrics are the rows of the comparison table; field names them
+// in csv, tsv and markdown.
+var comparisonMetrics = []struct {
+	name, field string
+	value       func(PeriodStats) int
+	change      func(Delta) Change
+	text        func(int) string
+}{
+	{"Orders", "orders", func(s PeriodStats) int { return s.Orders }, func(d Delta) Change { return d.Orders }, strconv.Itoa},
+	{"Spend", "amount_rupees", func(s PeriodStats) int { return s.Amount }, func(d Delta) Change { return d.Amount }, format.Rupees},
+	{"Avg basket", "avg_basket_rupees", func(s PeriodStats) int { return s.AvgBasket }, func(d Delta) Change { return d.AvgBasket }, format.Rupees},
+}
+
+// FormatComparison renders the metrics side by side with the change from
+// each earlier period, followed by the top products of each.
+func FormatComparison(c Comparison) string {
+	columns := []format.Column{
+		{Header: ""},
+		{Header: c.Current.Label, Right: true},
+		{Header: c.Previous.Label, Right: true},
+		{Header: "CHANGE", Right: true},
+	}
+	if c.LastYear != nil {
+		columns = append(columns, format.Column{Header: c.LastYear.Label, Right: true}, format.Column{Header: "CHANGE", Right: true})
+	}
+	var rows [][]string
+	for _, m := range comparisonMetrics {
+		row := []string{
+			m.name,
+			m.text(m.value(c.Current)),
+			m.text(m.value(c.Previous)),
+			formatChange(m.change(c.VsPrevious), m.text),
+		}
+		if c.LastYear != nil {
+			row = append(row, m.text(m.value(*c.LastYear)), formatChange(m.change(*c.VsLastYear), m.text))
+		}
+		rows = append(rows, row)
+	}
+
+	scope := "so far"
+	if c.ToDate {
+		scope = "to date, earlier periods cut at the same point"
+	}
+	lines := []string{
+		fmt.Sprintf("%s %s (%s)", strings.ToUpper(c.Period[:1])+c.Period[1:], c.Current.Label, scope),
+		"",
+		format.RenderTable(columns, rows, 0),
+		"",
+		"Top items:",
+	}
+	periods := []PeriodStats{c.Current, c.Previous}
+	if c.LastYear != nil {
+		periods = append(periods, *c.LastYear)
+	}
+	for _, p := range periods {
+		names := make([]string, len(p.TopItems))
+		for i, item := range p.TopItems {
+			names[i] = fmt.Sprintf("%s (%d)", item.Name, item.Orders)
+		}
+		if len(names) == 0 {
+			names = []string{"-"}
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", p.Label, strings.Join(names, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatChange renders a change as "+₹1,200 (+12.5%)"; the percentage is
+// "new" when the base was 0.
+func formatChange(c Change, text func(int) string) string {
+	sign := ""
+	if c.Absolute >= 0 {
+		sign = "+"
+	}
+	pct := "new"
+	switch {
+	case c.Percent != nil:
+		pct = fmt.Sprintf("%+.1f%%", *c.Percent)
+	case c.Absolute == 0:
+		pct = "-"
+	}
+	return fmt.Sprintf("%s%s (%s)", sign, text(c.Absolute), pct)
+}
+
+func comparisonRows(c Comparison) format.Tabular {
+	t := format.Tabular{Header: []string{"metric", "current", "previous", "change_previous", "percent_previous"}}
+	if c.LastYear != nil {
+		t.Header = append(t.Header, "last_year", "change_last_year", "percent_last_year")
+	}
+	for _, m := range comparisonMetrics {
+		row := []string{m.field, strconv.Itoa(m.value(c.Current)), strconv.Itoa(m.value(c.Previous))}
+		row = append(row, changeCells(m.change(c.VsPrevious))...)
+		if c.LastYear != nil {
+			row = append(row, strconv.Itoa(m.value(*c.LastYear)))
+			row = append(row, changeCells(m.change(*c.VsLastYear))...)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func changeCells(c Change) []string {
+	pct := ""
+	if c.Percent != nil {
+		pct = strconv.FormatFloat(*c.Percent, 'f', -1, 64)
+	}
+	return []string{strconv.Itoa(c.Absolute), pct}
+}
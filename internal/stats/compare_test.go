@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestCompare(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 9, 0, 0, 0, time.UTC) }
+	orders := []blink.Order{
+		{Date: day(5, 2), AmountRupees: 600, Items: []string{"Milk 1 L", "Bread"}},
+		{Date: day(5, 8), AmountRupees: 400, Items: []string{"Milk (500 ml)"}},
+		{Date: day(4, 5), AmountRupees: 500, Items: []string{"Eggs"}},
+		{Date: day(4, 20), AmountRupees: 1500, Items: []string{"Eggs", "Rice"}},
+		{Date: time.Date(2023, 5, 15, 9, 0, 0, 0, time.UTC), AmountRupees: 800, Items: []string{"Rice"}},
+	}
+
+	c, err := Compare(orders, now, PeriodMonth, false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Current.Label != "2024-05" || c.Current.Orders != 2 || c.Current.Amount != 1000 || c.Current.AvgBasket != 500 {
+		t.Fatalf("unexpected current period %+v", c.Current)
+	}
+	if len(c.Current.TopItems) != 1 || c.Current.TopItems[0] != (ItemCount{Name: "Milk (500 ml)", Orders: 2}) {
+		t.Fatalf("expected milk as the top item, got %+v", c.Current.TopItems)
+	}
+	if c.Previous.Label != "2024-04" || c.Previous.Amount != 2000 {
+		t.Fatalf("unexpected previous period %+v", c.Previous)
+	}
+	if d := c.VsPrevious.Amount; d.Absolute != -1000 || d.Percent == nil || *d.Percent != -50 {
+		t.Fatalf("expected -1000 (-50%%), got %+v", d)
+	}
+	if c.LastYear == nil || c.LastYear.Label != "2023-05" || c.VsLastYear.Orders.Absolute != 1 {
+		t.Fatalf("unexpected last year comparison %+v %+v", c.LastYear, c.VsLastYear)
+	}
+
+	// Cut at the same point, April only has the order of the 5th.
+	c, err = Compare(orders, now, PeriodMonth, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Previous.Orders != 1 || !c.Previous.End.Equal(time.Date(2024, 4, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected April cut at the 10th, got %+v", c.Previous)
+	}
+	if c.LastYear.Orders != 0 || c.VsLastYear.Amount.Percent != nil {
+		t.Fatalf("expected no orders to 10 May 2023 and no percentage, got %+v %+v", c.LastYear, c.VsLastYear)
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	c, err := Compare(nil, now, PeriodQuarter, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Current.Label != "2024-Q2" || c.Previous.Label != "2024-Q1" || c.LastYear.Label != "2023-Q2" {
+		t.Fatalf("unexpected quarter labels %s %s %s", c.Current.Label, c.Previous.Label, c.LastYear.Label)
+	}
+	c, err = Compare(nil, now, PeriodYear, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Current.Label != "2024" || c.Previous.Label != "2023" || c.LastYear != nil {
+		t.Fatalf("expected years without a separate last year, got %+v", c)
+	}
+	if _, err := Compare(nil, now, "week", false, 0); err == nil {
+		t.Fatal("expected an error for an unknown period")
+	}
+}
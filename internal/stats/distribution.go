@@ -0,0 +1,227 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// DefaultSmallOrder is the order value below which Blinkit has typically
+// charged a delivery fee, in rupees.
+const DefaultSmallOrder = 199
+
+// LargestOrders is the number of orders listed in Distribution.Largest.
+const LargestOrders = 5
+
+// histogramEdges are the lower bounds of the order value bins; the last bin
+// is open-ended.
+var histogramEdges = []int{0, 100, 200, 300, 400, 500, 750, 1000, 1500, 2000, 3000}
+
+// Distribution describes the spread of order values and basket sizes.
+// Amounts are rupees; orders without an amount count like any other.
+type Distribution struct {
+	Average int `json:"average_rupees"`
+	Median  int `json:"median_rupees"`
+	// P90 is the 90th percentile by the nearest-rank method.
+	P90           int            `json:"p90_rupees"`
+	ItemsPerOrder ItemsPerOrder  `json:"items_per_order"`
+	Histogram     []HistogramBin `json:"histogram"`
+	Largest       []OrderValue   `json:"largest"`
+	SmallOrders   SmallOrders    `json:"small_orders"`
+}
+
+// ItemsPerOrder summarizes how many items orders carry.
+type ItemsPerOrder struct {
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+	Max     int     `json:"max"`
+}
+
+// HistogramBin counts the orders with Min <= amount < Max; Max is 0 for the
+// last, open-ended bin.
+type HistogramBin struct {
+	Min    int `json:"min_rupees"`
+	Max    int `json:"max_rupees"`
+	Count  int `json:"count"`
+	Amount int `json:"amount_rupees"`
+}
+
+// Label names the bin's range, e.g. "₹1,000-1,499" or "₹3,000+".
+func (b HistogramBin) Label() string {
+	if b.Max == 0 {
+		return format.Rupees(b.Min) + "+"
+	}
+	return format.Rupees(b.Min) + "-" + strings.TrimPrefix(format.Rupees(b.Max-1), "₹")
+}
+
+// OrderValue is one of the largest orders.
+type OrderValue struct {
+	ID     string     `json:"id"`
+	Date   *time.Time `json:"date"`
+	Amount int        `json:"amount_rupees"`
+	Items  int        `json:"items"`
+}
+
+// SmallOrders counts orders below Threshold, which likely paid a delivery
+// fee. Share is their fraction of all orders, 0 to 1.
+type SmallOrders struct {
+	Threshold int     `json:"threshold_rupees"`
+	Count     int     `json:"count"`
+	Amount    int     `json:"amount_rupees"`
+	Share     float64 `json:"share"`
+}
+
+// BuildDistribution computes order value and basket size statistics;
+// orders below smallOrder rupees count as small.
+func BuildDistribution(orders []blink.Order, smallOrder int) Distribution {
+	d := Distribution{
+		Histogram:   make([]HistogramBin, len(histogramEdges)),
+		Largest:     []OrderValue{},
+		SmallOrders: SmallOrders{Threshold: smallOrder},
+	}
+	for i, edge := range histogramEdges {
+		d.Histogram[i].Min = edge
+		if i+1 < len(histogramEdges) {
+			d.Histogram[i].Max = histogramEdges[i+1]
+		}
+	}
+	if len(orders) == 0 {
+		return d
+	}
+
+	amounts := make([]float64, len(orders))
+	items := make([]float64, len(orders))
+	total := 0
+	for i, order := range orders {
+		amounts[i] = float64(order.AmountRupees)
+		items[i] = float64(len(order.Items))
+		total += order.AmountRupees
+		d.ItemsPerOrder.Max = max(d.ItemsPerOrder.Max, len(order.Items))
+
+		bin := sort.SearchInts(histogramEdges, order.AmountRupees+1) - 1
+		d.Histogram[max(bin, 0)].Count++
+		d.Histogram[max(bin, 0)].Amount += order.AmountRupees
+
+		if order.AmountRupees < smallOrder {
+			d.SmallOrders.Count++
+			d.SmallOrders.Amount += order.AmountRupees
+		}
+	}
+	n := float64(len(orders))
+	d.Average = int(math.Round(float64(total) / n))
+	d.Median = int(math.Round(median(amounts)))
+	d.P90 = int(percentile(amounts, 0.9))
+	d.ItemsPerOrder.Average = math.Round(sum(items)/n*10) / 10
+	d.ItemsPerOrder.Median = median(items)
+	d.SmallOrders.Share = math.Round(float64(d.SmallOrders.Count)/n*1000) / 1000
+
+	largest := append([]blink.Order(nil), orders...)
+	sort.SliceStable(largest, func(i, j int) bool { return largest[i].AmountRupees > largest[j].AmountRupees })
+	for _, order := range largest[:min(LargestOrders, len(largest))] {
+		value := OrderValue{ID: order.ID, Amount: order.AmountRupees, Items: len(order.Items)}
+		if !order.Date.IsZero() {
+			date := order.Date
+			value.Date = &date
+		}
+		d.Largest = append(d.Largest, value)
+	}
+	return d
+}
+
+// percentile returns the nearest-rank percentile p (0 to 1) of values.
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// histogramBuckets returns the bins as labeled buckets.
+func (d Distribution) histogramBuckets() []Bucket {
+	buckets := make([]Bucket, len(d.Histogram))
+	for i, bin := range d.Histogram {
+		buckets[i] = Bucket{Label: bin.Label(), Count: bin.Count, Amount: bin.Amount}
+	}
+	return buckets
+}
+
+var largestColumns = []format.Column{
+	{Header: "DATE"},
+	{Header: "AMOUNT", Right: true},
+	{Header: "ITEMS", Right: true},
+	{Header: "ORDER ID"},
+}
+
+// formatDistribution renders the order value section of the summary, with
+// bars of up to barWidth cells drawn with bar.
+func formatDistribution(d Distribution, total int, bar string) []string {
+	const barWidth = 30
+	lines := []string{
+		"Order value:",
+		fmt.Sprintf("  Average %s, median %s, 90th percentile %s", format.Rupees(d.Average), format.Rupees(d.Median), format.Rupees(d.P90)),
+		fmt.Sprintf("  Items per order: %s average, %s median, %d max",
+			strconv.FormatFloat(d.ItemsPerOrder.Average, 'f', -1, 64),
+			strconv.FormatFloat(d.ItemsPerOrder.Median, 'f', -1, 64),
+			d.ItemsPerOrder.Max),
+		fmt.Sprintf("  Small orders (under %s, likely with a delivery fee): %d of %d (%.0f%%), %s",
+			format.Rupees(d.SmallOrders.Threshold), d.SmallOrders.Count, total, 100*d.SmallOrders.Share, format.Rupees(d.SmallOrders.Amount)),
+	}
+
+	// Skip empty bins above the largest order.
+	last, most := 0, 0
+	for i, bin := range d.Histogram {
+		if bin.Count > 0 {
+			last = i
+		}
+		most = max(most, bin.Count)
+	}
+	labelWidth := 0
+	for _, bin := range d.Histogram[:last+1] {
+		labelWidth = max(labelWidth, format.DisplayWidth(bin.Label()))
+	}
+	for _, bin := range d.Histogram[:last+1] {
+		cells := 0
+		if most > 0 {
+			cells = int(math.Round(float64(bin.Count) / float64(most) * barWidth))
+		}
+		if bin.Count > 0 {
+			cells = max(cells, 1)
+		}
+		label := bin.Label()
+		pad := strings.Repeat(" ", labelWidth-format.DisplayWidth(label))
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("  %s%s  %s %d", pad, label, strings.Repeat(bar, cells), bin.Count), " "))
+	}
+
+	if len(d.Largest) > 0 {
+		lines = append(lines, "Largest orders:")
+		rows := make([][]string, len(d.Largest))
+		for i, o := range d.Largest {
+			date := ""
+			if o.Date != nil {
+				date = o.Date.Format("2006-01-02")
+			}
+			rows[i] = []string{date, format.Rupees(o.Amount), fmt.Sprintf("%d items", o.Items), o.ID}
+		}
+		table := format.RenderTable(largestColumns, rows, 0)
+		// Drop the header row; the section title says what the rows are.
+		_, body, _ := strings.Cut(table, "\n")
+		for _, line := range strings.Split(body, "\n") {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return lines
+}
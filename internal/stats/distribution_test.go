@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestBuildDistribution(t *testing.T) {
+	var orders []blink.Order
+	for i, amount := range []int{50, 150, 250, 250, 400, 600, 800, 1200, 2500, 5000} {
+		orders = append(orders, blink.Order{
+			ID:           string(rune('a' + i)),
+			Date:         time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC),
+			AmountRupees: amount,
+			Items:        make([]string, i%4+1),
+		})
+	}
+	d := BuildDistribution(orders, 199)
+	if d.Average != 1120 || d.Median != 500 || d.P90 != 2500 {
+		t.Fatalf("unexpected average/median/p90: %d %d %d", d.Average, d.Median, d.P90)
+	}
+	if d.ItemsPerOrder != (ItemsPerOrder{Average: 2.3, Median: 2, Max: 4}) {
+		t.Fatalf("unexpected items per order %+v", d.ItemsPerOrder)
+	}
+	if d.SmallOrders != (SmallOrders{Threshold: 199, Count: 2, Amount: 200, Share: 0.2}) {
+		t.Fatalf("unexpected small orders %+v", d.SmallOrders)
+	}
+	counts := map[string]int{}
+	for _, bin := range d.Histogram {
+		counts[bin.Label()] = bin.Count
+	}
+	if counts["₹200-299"] != 2 || counts["₹1,000-1,499"] != 1 || counts["₹3,000+"] != 1 || counts["₹300-399"] != 0 {
+		t.Fatalf("unexpected histogram %v", counts)
+	}
+	if len(d.Largest) != LargestOrders || d.Largest[0].Amount != 5000 || d.Largest[0].ID != "j" {
+		t.Fatalf("unexpected largest orders %+v", d.Largest)
+	}
+}
+
+func TestFormatDistributionTrimsEmptyBins(t *testing.T) {
+	orders := []blink.Order{{AmountRupees: 120}, {AmountRupees: 350}}
+	text := strings.Join(formatDistribution(BuildDistribution(orders, 199), len(orders), "#"), "\n")
+	if !strings.Contains(text, "₹100-199  ############################## 1") {
+		t.Fatalf("expected a full bar for the busiest bin, got:\n%s", text)
+	}
+	if strings.Contains(text, "₹400-499") {
+		t.Fatalf("expected bins above the largest order to be dropped, got:\n%s", text)
+	}
+}
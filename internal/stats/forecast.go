@@ -0,0 +1,236 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// RecentMonths is the number of complete months averaged into the baseline
+// of a forecast.
+const RecentMonths = 3
+
+// Forecast projects month-end and year-end spend. The model is:
+//
+//	month = spent so far + baseline × seasonality × share of the month left
+//	year  = spent so far + the month's remainder + baseline × seasonality
+//	        for each remaining month
+//
+// where seasonality compares each calendar month with the average month
+// once a full year of history exists, and the baseline is the average of the
+// last RecentMonths complete months with their seasonality taken out, or the
+// month's pace when there is no history.
+type Forecast struct {
+	AsOf  time.Time      `json:"as_of"`
+	Month PeriodForecast `json:"month"`
+	Year  PeriodForecast `json:"year"`
+	// Basis is "history" when the baseline comes from complete months and
+	// "pace" when it is the current month's pace.
+	Basis        string          `json:"basis"`
+	Baseline     int             `json:"baseline_rupees"`
+	Recent       []Bucket        `json:"recent_months"`
+	Seasonality  []SeasonalIndex `json:"seasonality"`
+	SeasonalFrom int             `json:"seasonal_years"`
+}
+
+// PeriodForecast is the projection for the current month or year.
+type PeriodForecast struct {
+	Label string `json:"label"`
+	Spent int    `json:"spent_rupees"`
+	// Elapsed is the share of the period gone by, 0 to 1.
+	Elapsed float64 `json:"elapsed"`
+	// Pace extrapolates Spent linearly, for reference.
+	Pace     int `json:"pace_rupees"`
+	Forecast int `json:"forecast_rupees"`
+}
+
+// SeasonalIndex is a calendar month's average spend relative to the average
+// month; 1 when there is less than a year of history. Months without spend
+// in the history get 0 and are taken as 1 when adjusting the baseline.
+type SeasonalIndex struct {
+	Month string  `json:"month"`
+	Index float64 `json:"index"`
+}
+
+// BuildForecast projects spend for the month and year containing now.
+func BuildForecast(orders []blink.Order, now time.Time) Forecast {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	yearStart := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+	f := Forecast{
+		AsOf:  now,
+		Month: PeriodForecast{Label: monthStart.Format("2006-01"), Elapsed: elapsed(monthStart, monthStart.AddDate(0, 1, 0), now)},
+		Year:  PeriodForecast{Label: yearStart.Format("2006"), Elapsed: elapsed(yearStart, yearStart.AddDate(1, 0, 0), now)},
+	}
+
+	// Spend per month, from the first order's month to the last complete one.
+	monthly := map[string]int{}
+	var first time.Time
+	for _, order := range orders {
+		if order.Date.IsZero() || order.Date.After(now) {
+			continue
+		}
+		d := order.Date.In(now.Location())
+		monthly[d.Format("2006-01")] += order.AmountRupees
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+		if !d.Before(monthStart) {
+			f.Month.Spent += order.AmountRupees
+		}
+		if !d.Before(yearStart) {
+			f.Year.Spent += order.AmountRupees
+		}
+	}
+	var complete []Bucket
+	if !first.IsZero() {
+		for m := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, now.Location()); m.Before(monthStart); m = m.AddDate(0, 1, 0) {
+			label := m.Format("2006-01")
+			complete = append(complete, Bucket{Label: label, Amount: monthly[label]})
+		}
+	}
+	f.Month.Pace = pace(f.Month)
+	f.Year.Pace = pace(f.Year)
+
+	f.Recent = complete[max(0, len(complete)-RecentMonths):]
+	if f.Recent == nil {
+		f.Recent = []Bucket{}
+	}
+	f.Seasonality, f.SeasonalFrom = seasonality(complete)
+	index := func(m time.Month) float64 { return f.Seasonality[m-1].Index }
+
+	f.Basis, f.Baseline = "pace", f.Month.Pace
+	if len(f.Recent) > 0 {
+		// Recent months are divided by their own index, so a festive
+		// October doesn't inflate the baseline for November.
+		total := 0.0
+		for _, b := range f.Recent {
+			m, _ := time.Parse("2006-01", b.Label)
+			i := index(m.Month())
+			if i <= 0 {
+				i = 1
+			}
+			total += float64(b.Amount) / i
+		}
+		f.Basis, f.Baseline = "history", int(math.Round(total/float64(len(f.Recent))))
+	}
+	seasonal := float64(f.Baseline)
+	if f.Basis == "history" {
+		seasonal *= index(now.Month())
+	}
+	rest := int(math.Round(seasonal * (1 - f.Month.Elapsed)))
+	f.Month.Forecast = f.Month.Spent + rest
+	f.Year.Forecast = f.Year.Spent + rest
+	for m := now.Month() + 1; m <= time.December; m++ {
+		f.Year.Forecast += int(math.Round(float64(f.Baseline) * index(m)))
+	}
+	return f
+}
+
+func elapsed(start, end, now time.Time) float64 {
+	return math.Round(now.Sub(start).Seconds()/end.Sub(start).Seconds()*1000) / 1000
+}
+
+func pace(p PeriodForecast) int {
+	if p.Elapsed <= 0 {
+		return p.Spent
+	}
+	return int(math.Round(float64(p.Spent) / p.Elapsed))
+}
+
+// seasonality averages each calendar month over the complete months and
+// divides by the average month. It needs 12 complete months; with fewer
+// every index is 1. It also returns the number of full years used.
+func seasonality(complete []Bucket) ([]SeasonalIndex, int) {
+	indexes := make([]SeasonalIndex, 12)
+	for i := range indexes {
+		indexes[i] = SeasonalIndex{Month: time.Month(i + 1).String()[:3], Index: 1}
+	}
+	if len(complete) < 12 {
+		return indexes, 0
+	}
+	var sums [12]float64
+	var counts [12]int
+	total := 0.0
+	for _, b := range complete {
+		m, _ := time.Parse("2006-01", b.Label)
+		sums[m.Month()-1] += float64(b.Amount)
+		counts[m.Month()-1]++
+		total += float64(b.Amount)
+	}
+	avg := total / float64(len(complete))
+	if avg == 0 {
+		return indexes, 0
+	}
+	for i := range indexes {
+		indexes[i].Index = math.Round(sums[i]/float64(counts[i])/avg*100) / 100
+	}
+	return indexes, len(complete) / 12
+}
+
+// ForecastOutput bundles every rendering of a forecast. The month and the
+// year are the records and rows.
+func ForecastOutput(f Forecast) format.Output {
+	rows := format.Tabular{Header: []string{"label", "spent_rupees", "elapsed", "pace_rupees", "forecast_rupees"}}
+	for _, p := range []PeriodForecast{f.Month, f.Year} {
+		rows.Rows = append(rows.Rows, []string{
+			p.Label,
+			strconv.Itoa(p.Spent),
+			strconv.FormatFloat(p.Elapsed, 'f', -1, 64),
+			strconv.Itoa(p.Pace),
+			strconv.Itoa(p.Forecast),
+		})
+	}
+	return format.Output{
+		Text:    func() string { return FormatForecast(f) },
+		Value:   f,
+		Records: []any{f.Month, f.Year},
+		Rows:    func() format.Tabular { return rows },
+	}
+}
+
+// FormatForecast renders the projections followed by their inputs.
+func FormatForecast(f Forecast) string {
+	days := int(math.Round(f.Month.Elapsed * float64(daysIn(f.AsOf))))
+	lines := []string{
+		fmt.Sprintf("Forecast (as of %s, %.0f%% of the month and %.0f%% of the year gone):",
+			f.AsOf.Format("2006-01-02"), 100*f.Month.Elapsed, 100*f.Year.Elapsed),
+		fmt.Sprintf("  %s: %s so far, forecast %s (pace alone %s)",
+			f.AsOf.Format("Jan 2006"), format.Rupees(f.Month.Spent), format.Rupees(f.Month.Forecast), format.Rupees(f.Month.Pace)),
+		fmt.Sprintf("  %s: %s so far, forecast %s (pace alone %s)",
+			f.Year.Label, format.Rupees(f.Year.Spent), format.Rupees(f.Year.Forecast), format.Rupees(f.Year.Pace)),
+	}
+	if f.Basis == "history" {
+		recent := make([]string, len(f.Recent))
+		for i, b := range f.Recent {
+			recent[i] = b.Label + " " + format.Rupees(b.Amount)
+		}
+		adjusted := ""
+		if f.SeasonalFrom > 0 {
+			adjusted = "seasonally adjusted "
+		}
+		lines = append(lines, fmt.Sprintf("  Baseline: %s a month, the %saverage of %s", format.Rupees(f.Baseline), adjusted, strings.Join(recent, ", ")))
+	} else {
+		lines = append(lines, fmt.Sprintf("  Baseline: %s a month, this month's pace after %d days (no complete months yet)", format.Rupees(f.Baseline), days))
+	}
+	if f.SeasonalFrom > 0 {
+		years := "1 year"
+		if f.SeasonalFrom > 1 {
+			years = fmt.Sprintf("%d years", f.SeasonalFrom)
+		}
+		lines = append(lines, fmt.Sprintf("  Seasonality: %s ×%.2f, from %s of history",
+			f.AsOf.Format("Jan"), f.Seasonality[f.AsOf.Month()-1].Index, years))
+	} else {
+		lines = append(lines, "  Seasonality: off, needs 12 complete months")
+	}
+	lines = append(lines, "  Forecast = spent so far + baseline × seasonality for the rest of the month and each remaining month")
+	return strings.Join(lines, "\n")
+}
+
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestBuildForecast(t *testing.T) {
+	// 16 April 2024 00:00 is half way through the month.
+	now := time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC)
+	orders := []blink.Order{
+		{Date: time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC), AmountRupees: 8000},
+		{Date: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), AmountRupees: 3000},
+		{Date: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), AmountRupees: 4000},
+		{Date: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), AmountRupees: 2500},
+		{Date: time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC), AmountRupees: 999}, // after now
+	}
+	f := BuildForecast(orders, now)
+	if f.Month.Label != "2024-04" || f.Month.Spent != 2500 || f.Month.Elapsed != 0.5 || f.Month.Pace != 5000 {
+		t.Fatalf("unexpected month %+v", f.Month)
+	}
+	if f.Basis != "history" || f.Baseline != 5000 || len(f.Recent) != 3 || f.SeasonalFrom != 0 {
+		t.Fatalf("expected the average of Jan-Mar without seasonality, got %+v", f)
+	}
+	// 2500 so far + 5000 for the second half of April.
+	if f.Month.Forecast != 5000 {
+		t.Fatalf("unexpected month forecast %d", f.Month.Forecast)
+	}
+	// 17500 so far this year + 2500 for April + 8 months of 5000.
+	if f.Year.Spent != 17500 || f.Year.Forecast != 60000 {
+		t.Fatalf("unexpected year %+v", f.Year)
+	}
+}
+
+func TestBuildForecastSeasonality(t *testing.T) {
+	now := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	var orders []blink.Order
+	// A year of 1000 a month with a 4000 November, then 1000 a month.
+	for m := time.Month(1); m <= 22; m++ {
+		amount := 1000
+		if m == 11 {
+			amount = 4000
+		}
+		orders = append(orders, blink.Order{Date: time.Date(2023, m, 5, 0, 0, 0, 0, time.UTC), AmountRupees: amount})
+	}
+	f := BuildForecast(orders, now)
+	if f.SeasonalFrom != 1 || f.Seasonality[10].Month != "Nov" || f.Seasonality[10].Index <= 1 {
+		t.Fatalf("expected November above average, got %+v", f.Seasonality)
+	}
+	if f.Month.Forecast <= f.Baseline {
+		t.Fatalf("expected the November forecast above the baseline %d, got %+v", f.Baseline, f.Month)
+	}
+}
+
+func TestBuildForecastWithoutHistory(t *testing.T) {
+	now := time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC)
+	f := BuildForecast([]blink.Order{{Date: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), AmountRupees: 1000}}, now)
+	if f.Basis != "pace" || f.Baseline != 2000 || f.Month.Forecast != 2000 || f.Year.Forecast != 18000 {
+		t.Fatalf("expected a pace-only forecast, got %+v", f)
+	}
+}
+
+func TestForecastOutput(t *testing.T) {
+	now := time.Date(2024, 4, 16, 0, 0, 0, 0, time.UTC)
+	f := BuildForecast([]blink.Order{{Date: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), AmountRupees: 1000}}, now)
+	out := ForecastOutput(f)
+	rows := out.Rows()
+	if len(out.Records) != 2 || len(rows.Rows) != 2 || rows.Rows[0][0] != "2024-04" || rows.Rows[1][4] != "18000" {
+		t.Fatalf("unexpected rows %v", rows.Rows)
+	}
+}
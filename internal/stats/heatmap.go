@@ -0,0 +1,210 @@
+package stats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// WeekdayLabels name the rows of a Heatmap, Monday first.
+var WeekdayLabels = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// LateNightHours are the hours counted by Heatmap.LateNight: 22:00 to 03:59.
+var LateNightHours = []int{22, 23, 0, 1, 2, 3}
+
+// Heatmap counts dated orders by weekday (Monday first) and hour of day.
+type Heatmap struct {
+	Counts  [7][24]int `json:"counts"`
+	Amounts [7][24]int `json:"amounts_rupees"`
+}
+
+// BuildHeatmap places each dated order in its weekday and hour cell.
+func BuildHeatmap(orders []blink.Order) Heatmap {
+	var h Heatmap
+	for _, order := range orders {
+		if order.Date.IsZero() {
+			continue
+		}
+		day := weekdayIndex(order.Date.Weekday())
+		hour := order.Date.Hour()
+		h.Counts[day][hour]++
+		h.Amounts[day][hour] += order.AmountRupees
+	}
+	return h
+}
+
+func weekdayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
+
+// Weekdays totals the heatmap rows, Monday first.
+func (h Heatmap) Weekdays() []Bucket {
+	buckets := make([]Bucket, 7)
+	for day := range h.Counts {
+		buckets[day].Label = WeekdayLabels[day]
+		for hour := range h.Counts[day] {
+			buckets[day].Count += h.Counts[day][hour]
+			buckets[day].Amount += h.Amounts[day][hour]
+		}
+	}
+	return buckets
+}
+
+// Hours totals the heatmap columns, labeled 00 to 23.
+func (h Heatmap) Hours() []Bucket {
+	buckets := make([]Bucket, 24)
+	for hour := range buckets {
+		buckets[hour].Label = fmt.Sprintf("%02d", hour)
+		for day := range h.Counts {
+			buckets[hour].Count += h.Counts[day][hour]
+			buckets[hour].Amount += h.Amounts[day][hour]
+		}
+	}
+	return buckets
+}
+
+// LateNight returns the orders and amount placed in LateNightHours.
+func (h Heatmap) LateNight() Bucket {
+	b := Bucket{Label: "22-04"}
+	hours := h.Hours()
+	for _, hour := range LateNightHours {
+		b.Count += hours[hour].Count
+		b.Amount += hours[hour].Amount
+	}
+	return b
+}
+
+func (h Heatmap) max() int {
+	m := 0
+	for day := range h.Counts {
+		for _, c := range h.Counts[day] {
+			m = max(m, c)
+		}
+	}
+	return m
+}
+
+// Shade ramps from an empty cell to the busiest one.
+var (
+	UnicodeShades = []string{"·", "░", "▒", "▓", "█"}
+	ASCIIShades   = []string{".", ":", "+", "*", "#"}
+)
+
+// RenderHeatmap draws weekdays against hours, two columns per hour, with
+// shades scaled to the busiest cell. A legend gives the count each shade
+// stands for.
+func RenderHeatmap(h Heatmap, shades []string) string {
+	peak := h.max()
+	level := func(c int) int {
+		if c == 0 || peak == 0 {
+			return 0
+		}
+		// Ceil so any order shows, and only the peak reaches the top shade.
+		return (c*(len(shades)-1) + peak - 1) / peak
+	}
+
+	var b strings.Builder
+	header := "     "
+	for hour := 0; hour < 24; hour += 3 {
+		header += fmt.Sprintf("%-6s", fmt.Sprintf("%02d", hour))
+	}
+	b.WriteString(strings.TrimRight(header, " ") + "\n")
+	for day := range h.Counts {
+		b.WriteString(WeekdayLabels[day] + "  ")
+		for _, c := range h.Counts[day] {
+			shade := shades[level(c)]
+			b.WriteString(shade + shade)
+		}
+		b.WriteString("\n")
+	}
+
+	legend := []string{shades[0] + " 0"}
+	for l := 1; l < len(shades); l++ {
+		lo := (l-1)*peak/(len(shades)-1) + 1
+		hi := l * peak / (len(shades) - 1)
+		if hi < lo {
+			continue
+		}
+		label := fmt.Sprint(lo)
+		if hi > lo {
+			label = fmt.Sprintf("%d-%d", lo, hi)
+		}
+		legend = append(legend, shades[l]+" "+label)
+	}
+	b.WriteString("\n     " + strings.Join(legend, "  ") + " orders")
+	return b.String()
+}
+
+// FormatTimeOfDay renders the heatmap with weekday totals, the busiest
+// hours and the late-night share.
+func FormatTimeOfDay(h Heatmap, shades []string) string {
+	lines := []string{RenderHeatmap(h, shades), ""}
+
+	total := 0
+	lines = append(lines, "By weekday:")
+	for _, b := range h.Weekdays() {
+		total += b.Count
+		lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
+	}
+
+	hours := h.Hours()
+	busiest := hours[0]
+	for _, b := range hours {
+		if b.Count > busiest.Count {
+			busiest = b
+		}
+	}
+	if busiest.Count > 0 {
+		lines = append(lines, fmt.Sprintf("Busiest hour: %s:00 (%d orders)", busiest.Label, busiest.Count))
+	}
+	late := h.LateNight()
+	if total > 0 {
+		lines = append(lines, fmt.Sprintf("Late night (22:00-04:00): %d orders (%.0f%%), ₹%d",
+			late.Count, 100*float64(late.Count)/float64(total), late.Amount))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HeatmapReport is the json output of 'stats heatmap'.
+type HeatmapReport struct {
+	Heatmap
+	Weekdays  []Bucket `json:"weekdays"`
+	Hours     []Bucket `json:"hours"`
+	LateNight Bucket   `json:"late_night"`
+}
+
+// HeatmapCell is one ndjson line of 'stats heatmap'.
+type HeatmapCell struct {
+	Weekday string `json:"weekday"`
+	Hour    int    `json:"hour"`
+	Count   int    `json:"count"`
+	Amount  int    `json:"amount_rupees"`
+}
+
+// HeatmapOutput bundles every rendering of a heatmap.
+func HeatmapOutput(h Heatmap, shades []string) format.Output {
+	var cells []any
+	rows := format.Tabular{Header: []string{"weekday", "hour", "count", "amount_rupees"}}
+	for day := range h.Counts {
+		for hour, c := range h.Counts[day] {
+			cell := HeatmapCell{Weekday: WeekdayLabels[day], Hour: hour, Count: c, Amount: h.Amounts[day][hour]}
+			cells = append(cells, cell)
+			rows.Rows = append(rows.Rows, []string{cell.Weekday, strconv.Itoa(hour), strconv.Itoa(c), strconv.Itoa(cell.Amount)})
+		}
+	}
+	return format.Output{
+		Text: func() string { return FormatTimeOfDay(h, shades) },
+		Value: HeatmapReport{
+			Heatmap:   h,
+			Weekdays:  h.Weekdays(),
+			Hours:     h.Hours(),
+			LateNight: h.LateNight(),
+		},
+		Records: cells,
+		Rows:    func() format.Tabular { return rows },
+	}
+}
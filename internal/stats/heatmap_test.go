@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestBuildHeatmap(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2024, 3, day, hour, 15, 0, 0, time.UTC) }
+	orders := []blink.Order{
+		{Date: at(4, 23), AmountRupees: 100}, // Monday
+		{Date: at(4, 23), AmountRupees: 50},
+		{Date: at(10, 9), AmountRupees: 200}, // Sunday
+		{Date: at(10, 1), AmountRupees: 30},
+		{AmountRupees: 999},
+	}
+	h := BuildHeatmap(orders)
+	if h.Counts[0][23] != 2 || h.Amounts[0][23] != 150 || h.Counts[6][9] != 1 {
+		t.Fatalf("unexpected cells %v", h.Counts)
+	}
+	if days := h.Weekdays(); days[0].Label != "Mon" || days[0].Count != 2 || days[6].Count != 2 {
+		t.Fatalf("unexpected weekday totals %+v", days)
+	}
+	if late := h.LateNight(); late.Count != 3 || late.Amount != 180 {
+		t.Fatalf("unexpected late-night bucket %+v", late)
+	}
+
+	out := RenderHeatmap(h, ASCIIShades)
+	lines := strings.Split(out, "\n")
+	if lines[0] != "     00    03    06    09    12    15    18    21" {
+		t.Fatalf("unexpected header %q", lines[0])
+	}
+	if want := "Mon  " + strings.Repeat("..", 23) + "##"; lines[1] != want {
+		t.Fatalf("unexpected Monday row %q", lines[1])
+	}
+	if !strings.Contains(lines[len(lines)-1], "# 2 orders") {
+		t.Fatalf("unexpected legend %q", lines[len(lines)-1])
+	}
+}
+
+func TestSummaryWeeklyDaily(t *testing.T) {
+	orders := []blink.Order{
+		{Date: time.Date(2024, 12, 30, 10, 0, 0, 0, time.UTC), AmountRupees: 10},
+		{Date: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), AmountRupees: 20},
+	}
+	s := BuildSummary(orders)
+	if len(s.Weekly) != 2 || s.Weekly[0].Label != "2024-W09" || s.Weekly[1].Label != "2025-W01" {
+		t.Fatalf("unexpected weekly buckets %+v", s.Weekly)
+	}
+	if len(s.Daily) != 2 || s.Daily[0].Label != "2024-03-01" {
+		t.Fatalf("unexpected daily buckets %+v", s.Daily)
+	}
+}
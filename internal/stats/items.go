@@ -0,0 +1,257 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// TrendMonths are the windows compared in ItemStat.Trend.
+var TrendMonths = []int{3, 6, 12}
+
+// ItemStat aggregates every purchase of one product. Amounts are estimates:
+// orders only carry a total, which is split evenly across their items.
+type ItemStat struct {
+	// Name is the most common spelling; Key the normalized name grouping
+	// the variants.
+	Name           string    `json:"name"`
+	Key            string    `json:"key"`
+	Variants       []string  `json:"variants"`
+	Orders         int       `json:"orders"`
+	EstimatedSpend int       `json:"estimated_spend_rupees"`
+	FirstPurchased time.Time `json:"first_purchased,omitzero"`
+	LastPurchased  time.Time `json:"last_purchased,omitzero"`
+	// AvgIntervalDays is the mean gap between purchases; 0 when bought once.
+	AvgIntervalDays float64 `json:"avg_interval_days"`
+	Trend           []Trend `json:"trend"`
+}
+
+// Trend counts the orders with an item in the last Months months and in the
+// Months before that.
+type Trend struct {
+	Months   int `json:"months"`
+	Orders   int `json:"orders"`
+	Previous int `json:"previous"`
+}
+
+// Item ranking keys for ItemStats.
+const (
+	ByOrders = "orders"
+	BySpend  = "spend"
+)
+
+var (
+	sizePattern  = regexp.MustCompile(`\b\d+(\.\d+)?\s*(x\s*\d+(\.\d+)?\s*)?(ml|l|ltr|litres?|liters?|g|gm|gms|grams?|kg|kgs|pcs?|pieces?|units?|packs?|n|nos?)\b`)
+	packPattern  = regexp.MustCompile(`\b(pack|combo|set) of \d+\b`)
+	parenPattern = regexp.MustCompile(`\([^)]*\d[^)]*\)`)
+	nonWordChars = regexp.MustCompile(`[^\p{L}\p{N}\p{M}&]+`)
+)
+
+// NormalizeItem folds case, whitespace and pack sizes so variants of one
+// product group together: "Amul Taaza Milk (500 ml)" and "amul taaza milk
+// 1 L" both become "amul taaza milk".
+func NormalizeItem(name string) string {
+	key := strings.ToLower(name)
+	key = parenPattern.ReplaceAllString(key, " ")
+	key = packPattern.ReplaceAllString(key, " ")
+	key = sizePattern.ReplaceAllString(key, " ")
+	key = strings.Join(strings.Fields(nonWordChars.ReplaceAllString(key, " ")), " ")
+	if key == "" {
+		key = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	}
+	return key
+}
+
+type itemAcc struct {
+	stat     ItemStat
+	spelling map[string]int
+	spend    float64
+	dates    []time.Time
+}
+
+// collectItems groups order items by normalized name.
+func collectItems(orders []blink.Order) map[string]*itemAcc {
+	accs := map[string]*itemAcc{}
+	for _, order := range orders {
+		if len(order.Items) == 0 {
+			continue
+		}
+		share := float64(order.AmountRupees) / float64(len(order.Items))
+		seen := map[string]bool{}
+		for _, item := range order.Items {
+			key := NormalizeItem(item)
+			acc, ok := accs[key]
+			if !ok {
+				acc = &itemAcc{stat: ItemStat{Key: key}, spelling: map[string]int{}}
+				accs[key] = acc
+			}
+			acc.spelling[item]++
+			acc.spend += share
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			acc.stat.Orders++
+			if !order.Date.IsZero() {
+				acc.dates = append(acc.dates, order.Date)
+			}
+		}
+	}
+	return accs
+}
+
+// ItemStats aggregates items across orders, ranked by the number of orders
+// containing them or by estimated spend. top <= 0 returns every item.
+func ItemStats(orders []blink.Order, now time.Time, by string, top int) []ItemStat {
+	accs := collectItems(orders)
+	items := make([]ItemStat, 0, len(accs))
+	for _, acc := range accs {
+		items = append(items, acc.finish(now))
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if by == BySpend && a.EstimatedSpend != b.EstimatedSpend {
+			return a.EstimatedSpend > b.EstimatedSpend
+		}
+		if a.Orders != b.Orders {
+			return a.Orders > b.Orders
+		}
+		if a.EstimatedSpend != b.EstimatedSpend {
+			return a.EstimatedSpend > b.EstimatedSpend
+		}
+		return a.Key < b.Key
+	})
+	if top > 0 && len(items) > top {
+		items = items[:top]
+	}
+	return items
+}
+
+func (acc *itemAcc) finish(now time.Time) ItemStat {
+	s := acc.stat
+	s.EstimatedSpend = int(math.Round(acc.spend))
+	for spelling := range acc.spelling {
+		s.Variants = append(s.Variants, spelling)
+	}
+	sort.Strings(s.Variants)
+	s.Name = acc.name()
+
+	sort.Slice(acc.dates, func(i, j int) bool { return acc.dates[i].Before(acc.dates[j]) })
+	if n := len(acc.dates); n > 0 {
+		s.FirstPurchased, s.LastPurchased = acc.dates[0], acc.dates[n-1]
+		if n > 1 {
+			days := s.LastPurchased.Sub(s.FirstPurchased).Hours() / 24
+			s.AvgIntervalDays = math.Round(days/float64(n-1)*10) / 10
+		}
+	}
+	for _, months := range TrendMonths {
+		t := Trend{Months: months}
+		start, prevStart := now.AddDate(0, -months, 0), now.AddDate(0, -2*months, 0)
+		for _, d := range acc.dates {
+			switch {
+			case d.After(now):
+			case !d.Before(start):
+				t.Orders++
+			case !d.Before(prevStart):
+				t.Previous++
+			}
+		}
+		s.Trend = append(s.Trend, t)
+	}
+	return s
+}
+
+// name is the most common spelling, the first alphabetically on ties.
+func (acc *itemAcc) name() string {
+	name := ""
+	for spelling, n := range acc.spelling {
+		if name == "" || n > acc.spelling[name] || (n == acc.spelling[name] && spelling < name) {
+			name = spelling
+		}
+	}
+	return name
+}
+
+// ItemsOutput bundles every rendering of item statistics; width fits the
+// table.
+func ItemsOutput(items []ItemStat, width int) format.Output {
+	records := make([]any, len(items))
+	for i, item := range items {
+		records[i] = item
+	}
+	return format.Output{
+		Text:    func() string { return FormatItems(items, width) },
+		Value:   items,
+		Records: records,
+		Rows:    func() format.Tabular { return itemRows(items) },
+	}
+}
+
+var itemColumns = []format.Column{
+	{Header: "ITEM", Wrap: true},
+	{Header: "ORDERS", Right: true},
+	{Header: "EST. SPEND", Right: true},
+	{Header: "FIRST"},
+	{Header: "LAST"},
+	{Header: "EVERY", Right: true},
+	{Header: "3M", Right: true},
+	{Header: "6M", Right: true},
+	{Header: "12M", Right: true},
+}
+
+// FormatItems renders item statistics as a table fitted to width. Trend
+// cells show the orders in the window and the change from the window
+// before it.
+func FormatItems(items []ItemStat, width int) string {
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		every := "-"
+		if item.AvgIntervalDays > 0 {
+			every = fmt.Sprintf("%.0fd", item.AvgIntervalDays)
+		}
+		row := []string{
+			item.Name,
+			strconv.Itoa(item.Orders),
+			format.Rupees(item.EstimatedSpend),
+			format.Date("2006-01-02", item.FirstPurchased),
+			format.Date("2006-01-02", item.LastPurchased),
+			every,
+		}
+		for _, t := range item.Trend {
+			row = append(row, fmt.Sprintf("%d (%+d)", t.Orders, t.Orders-t.Previous))
+		}
+		rows = append(rows, row)
+	}
+	return format.RenderTable(itemColumns, rows, width) +
+		"\nSpend is estimated by splitting each order's total evenly across its items."
+}
+
+func itemRows(items []ItemStat) format.Tabular {
+	t := format.Tabular{Header: []string{"name", "key", "orders", "estimated_spend_rupees", "first_purchased", "last_purchased", "avg_interval_days"}}
+	for _, m := range TrendMonths {
+		t.Header = append(t.Header, fmt.Sprintf("orders_%dm", m), fmt.Sprintf("previous_%dm", m))
+	}
+	for _, item := range items {
+		row := []string{
+			item.Name,
+			item.Key,
+			strconv.Itoa(item.Orders),
+			strconv.Itoa(item.EstimatedSpend),
+			format.Date(time.RFC3339, item.FirstPurchased),
+			format.Date(time.RFC3339, item.LastPurchased),
+			strconv.FormatFloat(item.AvgIntervalDays, 'f', -1, 64),
+		}
+		for _, tr := range item.Trend {
+			row = append(row, strconv.Itoa(tr.Orders), strconv.Itoa(tr.Previous))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestNormalizeItem(t *testing.T) {
+	cases := map[string]string{
+		"Amul Taaza Toned Fresh Milk (500 ml)": "amul taaza toned fresh milk",
+		"amul  taaza toned fresh milk 1 L":     "amul taaza toned fresh milk",
+		"Farm Eggs - Pack of 6":                "farm eggs",
+		"Maggi Noodles 4 x 70 g":               "maggi noodles",
+		"Lay's Classic Salted":                 "lay s classic salted",
+		"500 ml":                               "500 ml",
+	}
+	for in, want := range cases {
+		if got := NormalizeItem(in); got != want {
+			t.Fatalf("NormalizeItem(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestItemStats(t *testing.T) {
+	now := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	day := func(month, d int) time.Time { return time.Date(2024, time.Month(month), d, 10, 0, 0, 0, time.UTC) }
+	orders := []blink.Order{
+		{Date: day(1, 1), AmountRupees: 100, Items: []string{"Milk 500 ml", "Bread"}},
+		{Date: day(1, 11), AmountRupees: 90, Items: []string{"Milk (1 L)", "Eggs", "Butter"}},
+		{Date: day(11, 1), AmountRupees: 60, Items: []string{"milk 500 ml", "Milk 1 L"}},
+		{Date: day(12, 1), AmountRupees: 400, Items: []string{"Ghee"}},
+	}
+
+	items := ItemStats(orders, now, ByOrders, 0)
+	milk := items[0]
+	if milk.Key != "milk" || milk.Orders != 3 {
+		t.Fatalf("expected milk first with 3 orders, got %+v", milk)
+	}
+	// 100/2 + 90/3 + 60 (both lines of the third order).
+	if milk.EstimatedSpend != 140 {
+		t.Fatalf("unexpected estimated spend %d", milk.EstimatedSpend)
+	}
+	if !milk.FirstPurchased.Equal(day(1, 1)) || !milk.LastPurchased.Equal(day(11, 1)) {
+		t.Fatalf("unexpected purchase dates %v %v", milk.FirstPurchased, milk.LastPurchased)
+	}
+	if milk.AvgIntervalDays != 152.5 {
+		t.Fatalf("unexpected interval %v", milk.AvgIntervalDays)
+	}
+	if tr := milk.Trend[0]; tr.Months != 3 || tr.Orders != 1 || tr.Previous != 0 {
+		t.Fatalf("unexpected 3-month trend %+v", tr)
+	}
+	if tr := milk.Trend[2]; tr.Months != 12 || tr.Orders != 3 {
+		t.Fatalf("unexpected 12-month trend %+v", tr)
+	}
+	if len(milk.Variants) != 4 {
+		t.Fatalf("expected four spellings, got %v", milk.Variants)
+	}
+
+	bySpend := ItemStats(orders, now, BySpend, 1)
+	if len(bySpend) != 1 || bySpend[0].Key != "ghee" {
+		t.Fatalf("expected ghee to top spend, got %+v", bySpend)
+	}
+}
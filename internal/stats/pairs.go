@@ -0,0 +1,236 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// Association is a rule "orders with Antecedent also have Consequent" over
+// normalized item names.
+type Association struct {
+	Antecedent []string `json:"antecedent"`
+	Consequent string   `json:"consequent"`
+	// Orders contain every item of the rule; Support is their share of all
+	// orders with items.
+	Orders  int     `json:"orders"`
+	Support float64 `json:"support"`
+	// Confidence is the share of orders with the antecedent that also have
+	// the consequent.
+	Confidence float64 `json:"confidence"`
+	// Lift is Confidence over the consequent's own support; above 1 the
+	// items go together more often than chance.
+	Lift float64 `json:"lift"`
+}
+
+// PairsOptions controls Associations.
+type PairsOptions struct {
+	// MinSupport is the share of orders (0 to 1) an item set needs; at least
+	// two orders are always required.
+	MinSupport float64
+	// Size limits rules to pairs (2) or triples (3); 0 includes both.
+	Size int
+	// Item keeps the rules whose antecedent has an item matching it, by
+	// name, spelling or normalized name.
+	Item *regexp.Regexp
+	// Top limits the result; 0 returns every rule.
+	Top int
+}
+
+// Associations mines pairs and triples of items bought together with the
+// Apriori method and returns every rule derived from them, highest lift
+// first.
+func Associations(orders []blink.Order, opts PairsOptions) []Association {
+	accs := collectItems(orders)
+	baskets := make([][]string, 0, len(orders))
+	for _, order := range orders {
+		seen := map[string]bool{}
+		var basket []string
+		for _, item := range order.Items {
+			key := NormalizeItem(item)
+			if !seen[key] {
+				seen[key] = true
+				basket = append(basket, key)
+			}
+		}
+		if len(basket) > 0 {
+			sort.Strings(basket)
+			baskets = append(baskets, basket)
+		}
+	}
+	if len(baskets) == 0 {
+		return []Association{}
+	}
+	n := len(baskets)
+	minCount := max(2, int(math.Ceil(opts.MinSupport*float64(n))))
+
+	// Item sets are keyed by their sorted keys joined with "\x00".
+	counts := map[string]int{}
+	frequent := func(set ...string) bool { return counts[strings.Join(set, "\x00")] >= minCount }
+	for _, basket := range baskets {
+		for _, key := range basket {
+			counts[key]++
+		}
+	}
+	for _, basket := range baskets {
+		var items []string
+		for _, key := range basket {
+			if frequent(key) {
+				items = append(items, key)
+			}
+		}
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				counts[items[i]+"\x00"+items[j]]++
+			}
+		}
+	}
+	if opts.Size != 2 {
+		for _, basket := range baskets {
+			for i := range basket {
+				for j := i + 1; j < len(basket); j++ {
+					if !frequent(basket[i], basket[j]) {
+						continue
+					}
+					for k := j + 1; k < len(basket); k++ {
+						if frequent(basket[i], basket[k]) && frequent(basket[j], basket[k]) {
+							counts[basket[i]+"\x00"+basket[j]+"\x00"+basket[k]]++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	matches := func(key string) bool {
+		if opts.Item == nil {
+			return true
+		}
+		if opts.Item.MatchString(key) {
+			return true
+		}
+		for spelling := range accs[key].spelling {
+			if opts.Item.MatchString(spelling) {
+				return true
+			}
+		}
+		return false
+	}
+	var rules []Association
+	for set, count := range counts {
+		keys := strings.Split(set, "\x00")
+		if len(keys) < 2 || count < minCount || (opts.Size != 0 && len(keys) != opts.Size) {
+			continue
+		}
+		for i, consequent := range keys {
+			antecedent := append(append([]string{}, keys[:i]...), keys[i+1:]...)
+			if !slices.ContainsFunc(antecedent, matches) {
+				continue
+			}
+			confidence := float64(count) / float64(counts[strings.Join(antecedent, "\x00")])
+			rule := Association{
+				Consequent: accs[consequent].name(),
+				Orders:     count,
+				Support:    round(float64(count)/float64(n), 3),
+				Confidence: round(confidence, 3),
+				Lift:       round(confidence/(float64(counts[consequent])/float64(n)), 2),
+			}
+			for _, key := range antecedent {
+				rule.Antecedent = append(rule.Antecedent, accs[key].name())
+			}
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.Lift != b.Lift {
+			return a.Lift > b.Lift
+		}
+		if a.Orders != b.Orders {
+			return a.Orders > b.Orders
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		return a.label() < b.label()
+	})
+	if opts.Top > 0 && len(rules) > opts.Top {
+		rules = rules[:opts.Top]
+	}
+	if rules == nil {
+		rules = []Association{}
+	}
+	return rules
+}
+
+func round(v float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(v*scale) / scale
+}
+
+// label renders the rule as "Milk + Bread → Eggs".
+func (a Association) label() string {
+	return strings.Join(a.Antecedent, " + ") + " → " + a.Consequent
+}
+
+// PairsOutput bundles every rendering of association rules; width fits the
+// table.
+func PairsOutput(rules []Association, width int) format.Output {
+	records := make([]any, len(rules))
+	for i, r := range rules {
+		records[i] = r
+	}
+	return format.Output{
+		Text:    func() string { return FormatPairs(rules, width) },
+		Value:   rules,
+		Records: records,
+		Rows:    func() format.Tabular { return pairRows(rules) },
+	}
+}
+
+var pairColumns = []format.Column{
+	{Header: "BOUGHT TOGETHER", Wrap: true},
+	{Header: "ORDERS", Right: true},
+	{Header: "SUPPORT", Right: true},
+	{Header: "CONFIDENCE", Right: true},
+	{Header: "LIFT", Right: true},
+}
+
+// FormatPairs renders association rules as a table fitted to width.
+func FormatPairs(rules []Association, width int) string {
+	rows := make([][]string, 0, len(rules))
+	for _, r := range rules {
+		rows = append(rows, []string{
+			r.label(),
+			strconv.Itoa(r.Orders),
+			fmt.Sprintf("%.1f%%", 100*r.Support),
+			fmt.Sprintf("%.0f%%", 100*r.Confidence),
+			fmt.Sprintf("%.2f", r.Lift),
+		})
+	}
+	return format.RenderTable(pairColumns, rows, width) +
+		"\nConfidence is the share of orders with the left side that also have the right;" +
+		"\nlift above 1 means the items go together more often than chance."
+}
+
+func pairRows(rules []Association) format.Tabular {
+	t := format.Tabular{Header: []string{"antecedent", "consequent", "orders", "support", "confidence", "lift"}}
+	for _, r := range rules {
+		t.Rows = append(t.Rows, []string{
+			strings.Join(r.Antecedent, "; "),
+			r.Consequent,
+			strconv.Itoa(r.Orders),
+			strconv.FormatFloat(r.Support, 'f', -1, 64),
+			strconv.FormatFloat(r.Confidence, 'f', -1, 64),
+			strconv.FormatFloat(r.Lift, 'f', -1, 64),
+		})
+	}
+	return t
+}
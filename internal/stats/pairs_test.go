@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"regexp"
+	"testing"
+
+	"blinkcli/internal/blink"
+)
+
+func TestAssociations(t *testing.T) {
+	var orders []blink.Order
+	add := func(n int, items ...string) {
+		for range n {
+			orders = append(orders, blink.Order{Items: items})
+		}
+	}
+	add(4, "Bread", "Butter", "Jam")
+	add(2, "Bread", "Butter")
+	add(2, "Bread", "Milk 1 L")
+	add(2, "Milk (500 ml)")
+
+	rules := Associations(orders, PairsOptions{MinSupport: 0.2})
+	find := func(consequent string, antecedent ...string) Association {
+		for _, r := range rules {
+			if r.Consequent == consequent && len(r.Antecedent) == len(antecedent) {
+				match := true
+				for i := range antecedent {
+					match = match && r.Antecedent[i] == antecedent[i]
+				}
+				if match {
+					return r
+				}
+			}
+		}
+		t.Fatalf("no rule %v → %s in %+v", antecedent, consequent, rules)
+		return Association{}
+	}
+
+	r := find("Butter", "Bread")
+	if r.Orders != 6 || r.Support != 0.6 || r.Confidence != 0.75 || r.Lift != 1.25 {
+		t.Fatalf("unexpected Bread → Butter %+v", r)
+	}
+	r = find("Jam", "Bread", "Butter")
+	if r.Orders != 4 || r.Confidence != 0.667 || r.Lift != 1.67 {
+		t.Fatalf("unexpected Bread + Butter → Jam %+v", r)
+	}
+	// Both spellings of milk count as one item, bought twice with bread.
+	r = find("Milk (500 ml)", "Bread")
+	if r.Confidence != 0.25 || r.Lift != 0.63 {
+		t.Fatalf("unexpected Bread → Milk %+v", r)
+	}
+	if rules[0].Lift < rules[len(rules)-1].Lift {
+		t.Fatalf("expected rules sorted by lift, got %+v", rules)
+	}
+
+	rules = Associations(orders, PairsOptions{MinSupport: 0.5, Size: 2, Item: regexp.MustCompile("(?i)butter")})
+	if len(rules) != 1 || rules[0].Antecedent[0] != "Butter" || rules[0].Consequent != "Bread" {
+		t.Fatalf("expected only Butter → Bread, got %+v", rules)
+	}
+}
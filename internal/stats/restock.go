@@ -0,0 +1,202 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
+)
+
+// RestockItem is a product predicted to be bought again soon.
+type RestockItem struct {
+	Name          string    `json:"name"`
+	Key           string    `json:"key"`
+	Purchases     int       `json:"purchases"`
+	LastPurchased time.Time `json:"last_purchased"`
+	// IntervalDays is the median gap between purchases.
+	IntervalDays float64   `json:"interval_days"`
+	Due          time.Time `json:"due"`
+	// DueInDays is negative when the item is overdue.
+	DueInDays int `json:"due_in_days"`
+	// Confidence grows with the number of purchases and the regularity of
+	// the gaps, and fades once an item is long overdue; 0 to 1.
+	Confidence float64 `json:"confidence"`
+}
+
+// Restock lists products bought on at least two days whose next purchase,
+// predicted from the median gap, falls within the next `within` days or is
+// already past. Items below minConfidence are left out. The most overdue
+// come first.
+func Restock(orders []blink.Order, now time.Time, within int, minConfidence float64) []RestockItem {
+	var due []RestockItem
+	for _, acc := range collectItems(orders) {
+		stat := acc.finish(now)
+		days := purchaseDays(acc.dates)
+		if len(days) < 2 {
+			continue
+		}
+		gaps := make([]float64, len(days)-1)
+		for i := 1; i < len(days); i++ {
+			gaps[i-1] = days[i].Sub(days[i-1]).Hours() / 24
+		}
+		interval := math.Max(1, math.Round(median(gaps)))
+		last := stat.LastPurchased
+		dueAt := last.AddDate(0, 0, int(interval))
+		dueIn := int(math.Floor(dueAt.Sub(now).Hours() / 24))
+		if dueIn > within {
+			continue
+		}
+		item := RestockItem{
+			Name:          stat.Name,
+			Key:           stat.Key,
+			Purchases:     len(days),
+			LastPurchased: last,
+			IntervalDays:  interval,
+			Due:           dueAt,
+			DueInDays:     dueIn,
+			Confidence:    restockConfidence(gaps, interval, dueIn),
+		}
+		if item.Confidence < minConfidence {
+			continue
+		}
+		due = append(due, item)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].DueInDays != due[j].DueInDays {
+			return due[i].DueInDays < due[j].DueInDays
+		}
+		if due[i].Confidence != due[j].Confidence {
+			return due[i].Confidence > due[j].Confidence
+		}
+		return due[i].Key < due[j].Key
+	})
+	return due
+}
+
+// purchaseDays collapses sorted purchase times to one per calendar day.
+func purchaseDays(dates []time.Time) []time.Time {
+	var days []time.Time
+	for _, d := range dates {
+		y, m, dd := d.Date()
+		day := time.Date(y, m, dd, 0, 0, 0, 0, d.Location())
+		if len(days) == 0 || !days[len(days)-1].Equal(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// restockConfidence combines how much history there is (1 gap: 0.5,
+// 3 gaps: 0.75, ...), how regular the gaps are (coefficient of variation)
+// and, past twice the interval overdue, how likely the habit has stopped.
+func restockConfidence(gaps []float64, interval float64, dueIn int) float64 {
+	history := 1 - 1/float64(len(gaps)+1)
+	mean := 0.0
+	for _, g := range gaps {
+		mean += g
+	}
+	mean /= float64(len(gaps))
+	variance := 0.0
+	for _, g := range gaps {
+		variance += (g - mean) * (g - mean)
+	}
+	cv := 0.0
+	if mean > 0 {
+		cv = math.Sqrt(variance/float64(len(gaps))) / mean
+	}
+	c := history / (1 + cv)
+	if overdue := float64(-dueIn); overdue > 2*interval {
+		c *= 2 * interval / overdue
+	}
+	return math.Round(c*100) / 100
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// RestockOutput bundles every rendering of restock predictions.
+func RestockOutput(items []RestockItem, width int) format.Output {
+	records := make([]any, len(items))
+	for i, item := range items {
+		records[i] = item
+	}
+	return format.Output{
+		Text:    func() string { return FormatRestock(items, width) },
+		Value:   items,
+		Records: records,
+		Rows:    func() format.Tabular { return restockRows(items) },
+	}
+}
+
+var restockColumns = []format.Column{
+	{Header: "ITEM", Wrap: true},
+	{Header: "DUE"},
+	{Header: "LAST"},
+	{Header: "EVERY", Right: true},
+	{Header: "BOUGHT", Right: true},
+	{Header: "CONFIDENCE", Right: true},
+}
+
+// FormatRestock renders restock predictions as a table fitted to width.
+func FormatRestock(items []RestockItem, width int) string {
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []string{
+			item.Name,
+			dueText(item.DueInDays),
+			format.Date("2006-01-02", item.LastPurchased),
+			fmt.Sprintf("%.0fd", item.IntervalDays),
+			fmt.Sprintf("%d×", item.Purchases),
+			fmt.Sprintf("%.0f%%", item.Confidence*100),
+		})
+	}
+	return format.RenderTable(restockColumns, rows, width)
+}
+
+func dueText(days int) string {
+	switch {
+	case days < 0:
+		return fmt.Sprintf("%dd overdue", -days)
+	case days == 0:
+		return "today"
+	}
+	return fmt.Sprintf("in %dd", days)
+}
+
+// ShoppingList renders restock predictions as a Markdown checklist.
+func ShoppingList(items []RestockItem) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = "- [ ] " + item.Name
+	}
+	return strings.Join(lines, "\n")
+}
+
+func restockRows(items []RestockItem) format.Tabular {
+	t := format.Tabular{Header: []string{"name", "key", "purchases", "last_purchased", "interval_days", "due", "due_in_days", "confidence"}}
+	for _, item := range items {
+		t.Rows = append(t.Rows, []string{
+			item.Name,
+			item.Key,
+			strconv.Itoa(item.Purchases),
+			item.LastPurchased.Format(time.RFC3339),
+			strconv.FormatFloat(item.IntervalDays, 'f', -1, 64),
+			item.Due.Format(time.RFC3339),
+			strconv.Itoa(item.DueInDays),
+			strconv.FormatFloat(item.Confidence, 'f', 2, 64),
+		})
+	}
+	return t
+}
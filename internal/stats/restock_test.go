@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"blinkcli/internal/blink"
+)
+
+func TestRestock(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	ago := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	orders := []blink.Order{
+		{Date: ago(2), Items: []string{"Milk 1 L"}},
+		{Date: ago(9), Items: []string{"Milk (500 ml)", "Coffee"}},
+		{Date: ago(9), Items: []string{"Milk 1 L"}}, // same day counts once
+		{Date: ago(16), Items: []string{"Milk 1 L"}},
+		{Date: ago(23), Items: []string{"Milk 1 L", "Coffee"}},
+		{Date: ago(100), Items: []string{"Candles"}},
+		{Date: ago(104), Items: []string{"Candles"}},
+		{Date: ago(5), Items: []string{"Batteries"}},
+	}
+
+	items := Restock(orders, now, 7, 0)
+	if len(items) != 3 {
+		t.Fatalf("expected candles, coffee and milk, got %+v", items)
+	}
+	if items[0].Key != "candles" || items[0].DueInDays != -96 || items[0].Confidence >= 0.1 {
+		t.Fatalf("expected long-overdue candles first with low confidence, got %+v", items[0])
+	}
+	// Both are due in five days; the more certain prediction comes first.
+	milk, coffee := items[1], items[2]
+	if coffee.Key != "coffee" || coffee.IntervalDays != 14 || coffee.DueInDays != 5 {
+		t.Fatalf("unexpected coffee prediction %+v", coffee)
+	}
+	if milk.Key != "milk" || milk.Purchases != 4 || milk.IntervalDays != 7 || milk.DueInDays != 5 {
+		t.Fatalf("unexpected milk prediction %+v", milk)
+	}
+	if milk.Confidence != 0.75 || coffee.Confidence != 0.5 {
+		t.Fatalf("unexpected confidences milk %v, coffee %v", milk.Confidence, coffee.Confidence)
+	}
+
+	if got := Restock(orders, now, 7, 0.6); len(got) != 1 || got[0].Key != "milk" {
+		t.Fatalf("expected only milk above 0.6, got %+v", got)
+	}
+}
@@ -3,31 +3,76 @@ package stats
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"blinkcli/internal/blink"
+	"blinkcli/internal/format"
 )
 
+// Summary is also the json output of 'blinkcli stats'; see
+// docs/output-schema.md before changing field names.
 type Summary struct {
-	TotalOrders int
-	TotalAmount int
-	Monthly     []Bucket
-	Yearly      []Bucket
+	TotalOrders int      `json:"total_orders"`
+	TotalAmount int      `json:"total_amount_rupees"`
+	Monthly     []Bucket `json:"monthly"`
+	Yearly      []Bucket `json:"yearly"`
+	// Locations buckets orders by the saved location they were synced under,
+	// which is not necessarily where they were delivered.
+	Locations []Bucket `json:"locations"`
+	// Weekly buckets are labeled with ISO weeks (2024-W09), Daily with dates.
+	Weekly  []Bucket `json:"weekly"`
+	Daily   []Bucket `json:"daily"`
+	Heatmap Heatmap  `json:"heatmap"`
+	// Categories is filled in by the caller with CategoryBuckets, since it
+	// needs the profile's rules.
+	Categories []Bucket `json:"categories"`
+	// Distribution counts orders under DefaultSmallOrder as small; callers
+	// with another threshold replace it using BuildDistribution.
+	Distribution Distribution `json:"distribution"`
+}
+
+// SummaryOptions selects the optional sections of the text report.
+type SummaryOptions struct {
+	Weekly bool
+	Daily  bool
+	// Unicode draws histogram bars with block characters instead of '#'.
+	Unicode bool
+	// Chart replaces the monthly list with sparklines and bars of Metric
+	// (MetricAmount when empty), fitted to Width (80 when 0), and adds a
+	// sparkline to each year.
+	Chart  bool
+	Metric string
+	Width  int
 }
 
 type Bucket struct {
-	Label  string
-	Count  int
-	Amount int
+	Label  string `json:"label"`
+	Count  int    `json:"count"`
+	Amount int    `json:"amount_rupees"`
+}
+
+// BucketRecord is one ndjson line of the summary. Group is "total",
+// "yearly", "monthly", "location", "weekly", "daily", "weekday", "hour",
+// "category" or "order_value" (the histogram bins).
+type BucketRecord struct {
+	Group string `json:"group"`
+	Bucket
 }
 
 // BuildSummary aggregates orders into monthly and yearly buckets.
 func BuildSummary(orders []blink.Order) Summary {
 	monthly := map[string]*Bucket{}
 	yearly := map[string]*Bucket{}
+	locations := map[string]*Bucket{}
+	weekly := map[string]*Bucket{}
+	daily := map[string]*Bucket{}
 
 	for _, order := range orders {
+		if order.Location != "" {
+			addBucket(locations, order.Location, order)
+		}
 		if order.Date.IsZero() {
 			continue
 		}
@@ -36,14 +81,39 @@ func BuildSummary(orders []blink.Order) Summary {
 
 		addBucket(monthly, monthKey, order)
 		addBucket(yearly, yearKey, order)
+		addBucket(weekly, weekLabel(order.Date), order)
+		addBucket(daily, order.Date.Format("2006-01-02"), order)
 	}
 
 	return Summary{
-		TotalOrders: len(orders),
-		TotalAmount: sumAmounts(orders),
-		Monthly:     sortedBuckets(monthly),
-		Yearly:      sortedBuckets(yearly),
+		TotalOrders:  len(orders),
+		TotalAmount:  sumAmounts(orders),
+		Monthly:      sortedBuckets(monthly),
+		Yearly:       sortedBuckets(yearly),
+		Locations:    sortedBuckets(locations),
+		Weekly:       sortedBuckets(weekly),
+		Daily:        sortedBuckets(daily),
+		Heatmap:      BuildHeatmap(orders),
+		Categories:   []Bucket{},
+		Distribution: BuildDistribution(orders, DefaultSmallOrder),
+	}
+}
+
+// weekLabel names the ISO week of t, e.g. 2024-W09.
+func weekLabel(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// FilterLocation keeps the orders synced under the named location.
+func FilterLocation(orders []blink.Order, location string) []blink.Order {
+	filtered := make([]blink.Order, 0, len(orders))
+	for _, order := range orders {
+		if strings.EqualFold(order.Location, location) {
+			filtered = append(filtered, order)
+		}
 	}
+	return filtered
 }
 
 func addBucket(store map[string]*Bucket, key string, order blink.Order) {
@@ -76,26 +146,109 @@ func sortedBuckets(store map[string]*Bucket) []Bucket {
 	return buckets
 }
 
+// Records flattens the summary into bucket records, totals first.
+func (s Summary) Records() []BucketRecord {
+	records := []BucketRecord{{Group: "total", Bucket: Bucket{Label: "all", Count: s.TotalOrders, Amount: s.TotalAmount}}}
+	groups := []struct {
+		name    string
+		buckets []Bucket
+	}{
+		{"yearly", s.Yearly},
+		{"monthly", s.Monthly},
+		{"location", s.Locations},
+		{"weekly", s.Weekly},
+		{"daily", s.Daily},
+		{"weekday", s.Heatmap.Weekdays()},
+		{"hour", s.Heatmap.Hours()},
+		{"category", s.Categories},
+		{"order_value", s.Distribution.histogramBuckets()},
+	}
+	for _, g := range groups {
+		for _, b := range g.buckets {
+			records = append(records, BucketRecord{Group: g.name, Bucket: b})
+		}
+	}
+	return records
+}
+
+// SummaryOutput bundles every rendering of a summary. Only the table format
+// carries the generation time, so the others are reproducible.
+func SummaryOutput(summary Summary, opts SummaryOptions) format.Output {
+	records := summary.Records()
+	lines := make([]any, len(records))
+	rows := format.Tabular{Header: []string{"group", "label", "count", "amount_rupees"}}
+	for i, r := range records {
+		lines[i] = r
+		rows.Rows = append(rows.Rows, []string{r.Group, r.Label, strconv.Itoa(r.Count), strconv.Itoa(r.Amount)})
+	}
+	return format.Output{
+		Text:    func() string { return FormatSummary(summary, opts) },
+		Value:   summary,
+		Records: lines,
+		Rows:    func() format.Tabular { return rows },
+	}
+}
+
 // FormatSummary returns a short, human-readable report.
-func FormatSummary(summary Summary) string {
+func FormatSummary(summary Summary, opts SummaryOptions) string {
 	lines := []string{
 		fmt.Sprintf("Total: %d orders, ₹%d", summary.TotalOrders, summary.TotalAmount),
 	}
 
-	if len(summary.Yearly) > 0 {
+	if opts.Chart && len(summary.Yearly) > 0 {
+		lines = append(lines, formatYearly(summary, opts)...)
+	} else if len(summary.Yearly) > 0 {
 		lines = append(lines, "Yearly:")
 		for _, b := range summary.Yearly {
 			lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
 		}
 	}
 
-	if len(summary.Monthly) > 0 {
+	if opts.Chart {
+		lines = append(lines, formatChart(summary, opts)...)
+	} else if len(summary.Monthly) > 0 {
 		lines = append(lines, "Monthly:")
 		for _, b := range summary.Monthly {
 			lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
 		}
 	}
 
+	if opts.Weekly && len(summary.Weekly) > 0 {
+		lines = append(lines, "Weekly:")
+		for _, b := range summary.Weekly {
+			lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
+		}
+	}
+
+	if opts.Daily && len(summary.Daily) > 0 {
+		lines = append(lines, "Daily:")
+		for _, b := range summary.Daily {
+			lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
+		}
+	}
+
+	if summary.TotalOrders > 0 {
+		bar := "#"
+		if opts.Unicode {
+			bar = "█"
+		}
+		lines = append(lines, formatDistribution(summary.Distribution, summary.TotalOrders, bar)...)
+	}
+
+	if len(summary.Categories) > 0 {
+		lines = append(lines, "By category (estimated):")
+		for _, b := range summary.Categories {
+			lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
+		}
+	}
+
+	if len(summary.Locations) > 0 {
+		lines = append(lines, "By sync location (active at first sync, not the delivery address):")
+		for _, b := range summary.Locations {
+			lines = append(lines, fmt.Sprintf("  %s: %d orders, ₹%d", b.Label, b.Count, b.Amount))
+		}
+	}
+
 	// Include timestamp to show when stats were generated.
 	lines = append(lines, fmt.Sprintf("Generated at %s", time.Now().Format(time.RFC3339)))
 	return strings.Join(lines, "\n")
@@ -18,8 +18,14 @@ type Store struct {
 	Path string
 }
 
+// New returns the store of the active profile.
 func New() (*Store, error) {
-	path, err := config.OrdersPath()
+	return ForProfile(config.ActiveProfile())
+}
+
+// ForProfile returns the store of a named profile.
+func ForProfile(profile string) (*Store, error) {
+	path, err := config.OrdersPathFor(profile)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +60,18 @@ func (s *Store) Save(orders []blink.Order) error {
 
 // MergeOrders merges incoming orders into existing ones, returning the merged list and new count.
 func MergeOrders(existing, incoming []blink.Order) ([]blink.Order, int) {
+	return mergeWith(existing, incoming, mergeOrder)
+}
+
+// CombineOrders merges the stores of several profiles. An order in more
+// than one store keeps the larger SyncCount rather than their sum, since the
+// syncs of different profiles are separate histories.
+func CombineOrders(existing, incoming []blink.Order) []blink.Order {
+	merged, _ := mergeWith(existing, incoming, combineOrder)
+	return merged
+}
+
+func mergeWith(existing, incoming []blink.Order, merge func(existing, incoming blink.Order) blink.Order) ([]blink.Order, int) {
 	seen := map[string]blink.Order{}
 	unknownExisting := 0
 	for _, order := range existing {
@@ -73,7 +91,9 @@ func MergeOrders(existing, incoming []blink.Order) ([]blink.Order, int) {
 			key = fmt.Sprintf("unknown-incoming-%d", unknownIncoming)
 			unknownIncoming++
 		}
-		if _, ok := seen[key]; !ok {
+		if prev, ok := seen[key]; ok {
+			seen[key] = merge(prev, order)
+		} else {
 			seen[key] = order
 			newCount++
 		}
@@ -96,6 +116,38 @@ func MergeOrders(existing, incoming []blink.Order) ([]blink.Order, int) {
 	return merged, newCount
 }
 
+// mergeOrder combines a stored order with a newly fetched copy. The sync
+// counts add up only when the copy comes from a later sync, so an order
+// seen on two pages of one sync counts once.
+func mergeOrder(existing, incoming blink.Order) blink.Order {
+	merged := combineOrder(existing, incoming)
+	if incoming.LastSyncedAt.After(existing.LastSyncedAt) {
+		merged.SyncCount = existing.SyncCount + incoming.SyncCount
+	}
+	return merged
+}
+
+// combineOrder combines two copies of one order: the fields that change over
+// an order's life (status, deeplink) come from the copy synced last, the
+// sync times span both and the larger SyncCount is kept.
+func combineOrder(existing, incoming blink.Order) blink.Order {
+	merged := existing
+	if incoming.LastSyncedAt.After(existing.LastSyncedAt) {
+		if incoming.Status != "" {
+			merged.Status = incoming.Status
+		}
+		merged.LastSyncedAt = incoming.LastSyncedAt
+	}
+	if merged.Deeplink == "" || (incoming.LastSyncedAt.After(existing.LastSyncedAt) && incoming.Deeplink != "") {
+		merged.Deeplink = incoming.Deeplink
+	}
+	if merged.FirstSyncedAt.IsZero() || (!incoming.FirstSyncedAt.IsZero() && incoming.FirstSyncedAt.Before(merged.FirstSyncedAt)) {
+		merged.FirstSyncedAt = incoming.FirstSyncedAt
+	}
+	merged.SyncCount = max(existing.SyncCount, incoming.SyncCount)
+	return merged
+}
+
 func orderKey(order blink.Order) string {
 	if order.ID != "" {
 		return "id:" + order.ID
@@ -32,3 +32,45 @@ func TestStoreSaveLoad(t *testing.T) {
 		t.Fatalf("expected ids to persist, got %+v", loaded)
 	}
 }
+
+func TestMergeOrdersKeepsSyncHistory(t *testing.T) {
+	first := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	existing := []blink.Order{
+		{ID: "1", Status: "Arriving", FirstSyncedAt: first, LastSyncedAt: first, SyncCount: 1},
+	}
+	incoming := []blink.Order{
+		{ID: "1", Status: "Delivered", Deeplink: "grofers://order?order_id=1", FirstSyncedAt: second, LastSyncedAt: second, SyncCount: 1},
+	}
+
+	merged, newCount := MergeOrders(existing, incoming)
+	if newCount != 0 || len(merged) != 1 {
+		t.Fatalf("expected one existing order, got %d new, %+v", newCount, merged)
+	}
+	got := merged[0]
+	if got.Status != "Delivered" || got.Deeplink == "" {
+		t.Fatalf("expected status and deeplink from the newer sync, got %+v", got)
+	}
+	if !got.FirstSyncedAt.Equal(first) || !got.LastSyncedAt.Equal(second) || got.SyncCount != 2 {
+		t.Fatalf("unexpected sync history %+v", got)
+	}
+}
+
+func TestMergeOrdersCountsEachSyncOnce(t *testing.T) {
+	first := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	existing := []blink.Order{{ID: "1", FirstSyncedAt: first, LastSyncedAt: first, SyncCount: 3}}
+	page := blink.Order{ID: "1", FirstSyncedAt: second, LastSyncedAt: second, SyncCount: 1}
+
+	merged, _ := MergeOrders(existing, []blink.Order{page})
+	merged, _ = MergeOrders(merged, []blink.Order{page})
+	if merged[0].SyncCount != 4 {
+		t.Fatalf("expected one sync counted once across pages, got %d", merged[0].SyncCount)
+	}
+
+	other := []blink.Order{{ID: "1", FirstSyncedAt: first, LastSyncedAt: second, SyncCount: 2}}
+	combined := CombineOrders(merged, other)
+	if len(combined) != 1 || combined[0].SyncCount != 4 || !combined[0].LastSyncedAt.Equal(second) {
+		t.Fatalf("expected profiles to keep the larger count, got %+v", combined)
+	}
+}